@@ -0,0 +1,158 @@
+package addon
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+)
+
+// AppDataMeta holds the fields every add-on result in file AppData has
+type AppDataMeta struct {
+	// Version is the add-on version the result was produced by
+	Version string `json:"version"`
+
+	// CreatedAt is a date and time when the result was created
+	CreatedAt *config.Time `json:"datetime_created"`
+
+	// UpdatedAt is a date and time when the result was last updated
+	UpdatedAt *config.Time `json:"datetime_updated"`
+}
+
+// RawResult holds a result of an add-on with no typed decoder
+type RawResult struct {
+	AppDataMeta
+	Data json.RawMessage `json:"data"`
+}
+
+// ClamAVResult holds the uc_clamav_virus_scan add-on result
+type ClamAVResult struct {
+	AppDataMeta
+	Data ClamAVData `json:"data"`
+}
+
+// ClamAVData holds the virus scan verdict
+type ClamAVData struct {
+	Infected     bool   `json:"infected"`
+	InfectedWith string `json:"infected_with"`
+}
+
+// IsInfected reports whether the scan found a virus
+func (r ClamAVResult) IsInfected() bool { return r.Data.Infected }
+
+// Threat returns the name of the virus found, if any
+func (r ClamAVResult) Threat() string { return r.Data.InfectedWith }
+
+// RekognitionLabelsResult holds the aws_rekognition_detect_labels add-on
+// result
+type RekognitionLabelsResult struct {
+	AppDataMeta
+	Data RekognitionLabelsData `json:"data"`
+}
+
+// RekognitionLabelsData holds labels detected by AWS Rekognition
+type RekognitionLabelsData struct {
+	LabelModelVersion string             `json:"LabelModelVersion"`
+	Labels            []RekognitionLabel `json:"Labels"`
+}
+
+// RekognitionLabel is an object or a concept detected in an image
+type RekognitionLabel struct {
+	Name       string                `json:"Name"`
+	Confidence float64               `json:"Confidence"`
+	Instances  []RekognitionInstance `json:"Instances"`
+	Parents    []RekognitionParent   `json:"Parents"`
+}
+
+// RekognitionInstance is a single occurrence of a label in an image
+type RekognitionInstance struct {
+	BoundingBox BoundingBox `json:"BoundingBox"`
+	Confidence  float64     `json:"Confidence"`
+}
+
+// RekognitionParent is a label the detected label belongs to
+type RekognitionParent struct {
+	Name string `json:"Name"`
+}
+
+// BoundingBox holds an image area as ratios of the image dimensions
+type BoundingBox struct {
+	Width  float64 `json:"Width"`
+	Height float64 `json:"Height"`
+	Left   float64 `json:"Left"`
+	Top    float64 `json:"Top"`
+}
+
+// Label returns the detected label by its name
+func (r RekognitionLabelsResult) Label(name string) (RekognitionLabel, bool) {
+	i := slices.IndexFunc(r.Data.Labels, func(l RekognitionLabel) bool {
+		return l.Name == name
+	})
+	if i < 0 {
+		return RekognitionLabel{}, false
+	}
+	return r.Data.Labels[i], true
+}
+
+// HasLabel reports whether the label has been detected with at least
+// minConfidence percent confidence
+func (r RekognitionLabelsResult) HasLabel(name string, minConfidence float64) bool {
+	l, ok := r.Label(name)
+	return ok && l.Confidence >= minConfidence
+}
+
+// Names returns names of the detected labels
+func (r RekognitionLabelsResult) Names() []string {
+	names := make([]string, 0, len(r.Data.Labels))
+	for _, l := range r.Data.Labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// RekognitionModerationResult holds the
+// aws_rekognition_detect_moderation_labels add-on result
+type RekognitionModerationResult struct {
+	AppDataMeta
+	Data RekognitionModerationData `json:"data"`
+}
+
+// RekognitionModerationData holds unsafe content labels detected by
+// AWS Rekognition
+type RekognitionModerationData struct {
+	ModerationModelVersion string            `json:"ModerationModelVersion"`
+	ModerationLabels       []ModerationLabel `json:"ModerationLabels"`
+}
+
+// ModerationLabel is a detected unsafe content category
+type ModerationLabel struct {
+	Name       string  `json:"Name"`
+	ParentName string  `json:"ParentName"`
+	Confidence float64 `json:"Confidence"`
+}
+
+// Flagged returns moderation labels detected with at least minConfidence
+// percent confidence
+func (r RekognitionModerationResult) Flagged(minConfidence float64) []ModerationLabel {
+	var flagged []ModerationLabel
+	for _, l := range r.Data.ModerationLabels {
+		if l.Confidence >= minConfidence {
+			flagged = append(flagged, l)
+		}
+	}
+	return flagged
+}
+
+// RemoveBGResult holds the remove_bg add-on result
+type RemoveBGResult struct {
+	AppDataMeta
+	Data RemoveBGData `json:"data"`
+}
+
+// RemoveBGData holds background removal details
+type RemoveBGData struct {
+	ForegroundType string `json:"foreground_type"`
+}
+
+// ForegroundType returns the detected foreground type, e.g. "person"
+func (r RemoveBGResult) ForegroundType() string { return r.Data.ForegroundType }
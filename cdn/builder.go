@@ -0,0 +1,267 @@
+// Package cdn builds and parses Uploadcare CDN URLs with image processing
+// operations.
+//
+// Builder validates every operation as it is added and produces the URL
+// against the client CDN base:
+//
+//	imgURL, err := cdn.New(fileID).
+//		Resize(800, 0).
+//		Format(cdn.FormatWebP).
+//		Quality(cdn.QualitySmart).
+//		Filename("photo.webp").
+//		URL(client)
+//	if err != nil {
+//		// handle invalid operations
+//	}
+//
+// Builders are immutable, every method returns a new one, so a common
+// chain can be shared:
+//
+//	base := cdn.New(fileID).Format(cdn.FormatAuto)
+//	thumb := base.SmartCrop(200, 200)
+//	full := base.Preview(0, 0)
+//
+// Service requests image details the CDN computes, e.g. its main colors.
+package cdn
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// Builder errors
+var (
+	ErrInvalidUUID     = errors.New("cdn: invalid file UUID")
+	ErrInvalidGroupID  = errors.New("cdn: invalid group ID")
+	ErrInvalidNth      = errors.New("cdn: group file index out of range")
+	ErrInvalidFilename = errors.New("cdn: filename must not be empty or contain slashes")
+	ErrNoCDNBase       = errors.New("cdn: client has no CDN base")
+)
+
+const uuidPattern = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+
+var (
+	uuidRe    = regexp.MustCompile(`^` + uuidPattern + `$`)
+	groupIDRe = regexp.MustCompile(`^` + uuidPattern + `~([1-9]\d*)$`)
+)
+
+// Builder builds CDN URLs of a file with image processing operations.
+// The first invalid operation or argument is reported by Err, Path and
+// URL.
+type Builder struct {
+	uuid     string
+	groupID  string
+	nth      int
+	ops      []Op
+	filename string
+	err      error
+}
+
+// New returns a builder of the file URLs
+func New(uuid string) *Builder {
+	b := &Builder{uuid: uuid}
+	if !uuidRe.MatchString(uuid) {
+		b.err = fmt.Errorf("%w: %q", ErrInvalidUUID, uuid)
+	}
+	return b
+}
+
+// NewGroupFile returns a builder of the URLs of the nth file of the group,
+// counting from zero. The group ID looks like UUID~N, where N is the number
+// of files in the group.
+func NewGroupFile(groupID string, nth int) *Builder {
+	b := &Builder{groupID: groupID, nth: nth}
+	m := groupIDRe.FindStringSubmatch(groupID)
+	if m == nil {
+		b.err = fmt.Errorf("%w: %q", ErrInvalidGroupID, groupID)
+		return b
+	}
+	if count, _ := strconv.Atoi(m[1]); nth < 0 || nth >= count {
+		b.err = fmt.Errorf("%w: %d is out of 0..%d", ErrInvalidNth, nth, count-1)
+	}
+	return b
+}
+
+// UUID returns the file ID, empty for group files
+func (b *Builder) UUID() string { return b.uuid }
+
+// GroupID returns the group ID and the file index for group files
+func (b *Builder) GroupID() (string, int) { return b.groupID, b.nth }
+
+// Ops returns the operations in the order they are applied
+func (b *Builder) Ops() []Op { return slices.Clone(b.ops) }
+
+// Err returns the first error occurred while building, if any
+func (b *Builder) Err() error { return b.err }
+
+// Op returns a builder with the operation appended
+func (b *Builder) Op(op Op) *Builder {
+	nb := *b
+	if nb.err != nil {
+		return &nb
+	}
+	if err := op.validate(); err != nil {
+		nb.err = err
+		return &nb
+	}
+	nb.ops = append(slices.Clip(b.ops), op)
+	return &nb
+}
+
+// Resize resizes the image to the width and height. If one of them is
+// zero, the image aspect ratio is preserved.
+func (b *Builder) Resize(width, height int) *Builder {
+	return b.Op(ResizeOp{Width: width, Height: height})
+}
+
+// ScaleCrop scales the image down to cover the size and crops it according
+// to the alignment, the center if omitted
+func (b *Builder) ScaleCrop(width, height int, alignment ...Alignment) *Builder {
+	return b.Op(ScaleCropOp{Width: width, Height: height, Alignment: first(alignment)})
+}
+
+// SmartCrop scales the image down to cover the size and crops it keeping
+// the most meaningful parts of the image
+func (b *Builder) SmartCrop(width, height int) *Builder {
+	return b.Op(ScaleCropOp{Width: width, Height: height, Alignment: AlignSmart})
+}
+
+// Crop crops the area of the size positioned according to the alignment,
+// the top left corner if omitted
+func (b *Builder) Crop(width, height int, alignment ...Alignment) *Builder {
+	return b.Op(CropOp{Width: width, Height: height, Alignment: first(alignment)})
+}
+
+// Preview downscales the image to fit the size, 2048x2048 if both of the
+// dimensions are zero
+func (b *Builder) Preview(width, height int) *Builder {
+	return b.Op(PreviewOp{Width: width, Height: height})
+}
+
+// Format converts the image to the format
+func (b *Builder) Format(format ImageFormat) *Builder {
+	return b.Op(FormatOp{Format: format})
+}
+
+// Quality sets the compression quality
+func (b *Builder) Quality(quality Quality) *Builder {
+	return b.Op(QualityOp{Quality: quality})
+}
+
+// Progressive turns progressive JPEG encoding on or off
+func (b *Builder) Progressive(enabled bool) *Builder {
+	return b.Op(ProgressiveOp{Enabled: enabled})
+}
+
+// Rotate rotates the image counterclockwise by the angle, a multiple of 90
+func (b *Builder) Rotate(angle int) *Builder {
+	return b.Op(RotateOp{Angle: angle})
+}
+
+// Flip flips the image vertically
+func (b *Builder) Flip() *Builder { return b.Op(FlipOp{}) }
+
+// Mirror flips the image horizontally
+func (b *Builder) Mirror() *Builder { return b.Op(MirrorOp{}) }
+
+// Grayscale desaturates the image
+func (b *Builder) Grayscale() *Builder { return b.Op(GrayscaleOp{}) }
+
+// Blur blurs the image, zero strength applies the default one
+func (b *Builder) Blur(strength int) *Builder {
+	return b.Op(BlurOp{Strength: strength})
+}
+
+// Sharpen sharpens the image, zero strength applies the default one
+func (b *Builder) Sharpen(strength int) *Builder {
+	return b.Op(SharpenOp{Strength: strength})
+}
+
+// Overlay puts another image over the image, see OverlayOp for the
+// optional parameters
+func (b *Builder) Overlay(op OverlayOp) *Builder { return b.Op(op) }
+
+// SetFill sets the background color as 3 or 6 hex digits
+func (b *Builder) SetFill(color string) *Builder {
+	return b.Op(SetFillOp{Color: color})
+}
+
+// Stretch sets the stretch mode of the resizing operations
+func (b *Builder) Stretch(mode StretchMode) *Builder {
+	return b.Op(StretchOp{Mode: mode})
+}
+
+// StripMeta strips the image metadata
+func (b *Builder) StripMeta(mode StripMetaMode) *Builder {
+	return b.Op(StripMetaOp{Mode: mode})
+}
+
+// Filename returns a builder with the trailing file name of the URL set
+func (b *Builder) Filename(name string) *Builder {
+	nb := *b
+	if nb.err == nil && (name == "" || strings.Contains(name, "/")) {
+		nb.err = fmt.Errorf("%w: %q", ErrInvalidFilename, name)
+	}
+	nb.filename = name
+	return &nb
+}
+
+// Path returns the URL path relative to the CDN base, e.g.
+// "uuid/-/resize/800x/photo.jpg" or "uuid~2/nth/1/-/resize/800x/"
+func (b *Builder) Path() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+
+	var sb strings.Builder
+	if b.groupID != "" {
+		fmt.Fprintf(&sb, "%s/nth/%d/", b.groupID, b.nth)
+	} else {
+		sb.WriteString(b.uuid)
+		sb.WriteByte('/')
+	}
+	for _, op := range b.ops {
+		sb.WriteString("-/")
+		sb.WriteString(op.Name())
+		sb.WriteByte('/')
+		for _, arg := range op.Args() {
+			sb.WriteString(arg)
+			sb.WriteByte('/')
+		}
+	}
+	sb.WriteString(url.PathEscape(b.filename))
+	return sb.String(), nil
+}
+
+// URL returns the absolute URL against the client CDN base, see
+// ucare.ClientCDNBase
+func (b *Builder) URL(client ucare.Client) (string, error) {
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		return "", ErrNoCDNBase
+	}
+	return b.URLWithBase(base)
+}
+
+// URLWithBase returns the absolute URL against the CDN base
+func (b *Builder) URLWithBase(base string) (string, error) {
+	p, err := b.Path()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(base, "/") + "/" + p, nil
+}
+
+func first(alignment []Alignment) Alignment {
+	if len(alignment) == 0 {
+		return ""
+	}
+	return alignment[0]
+}
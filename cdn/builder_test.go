@@ -0,0 +1,141 @@
+package cdn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+const (
+	testUUID    = "a1b2c3d4-e5f6-4a5b-8c9d-0e1f2a3b4c5d"
+	overlayUUID = "b1b2c3d4-e5f6-4a5b-8c9d-0e1f2a3b4c5d"
+)
+
+func TestBuilder_Path(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{"plain", New(testUUID), ""},
+		{"resize_width", New(testUUID).Resize(800, 0), "-/resize/800x/"},
+		{"resize_height", New(testUUID).Resize(0, 600), "-/resize/x600/"},
+		{"scale_crop", New(testUUID).ScaleCrop(200, 100), "-/scale_crop/200x100/"},
+		{"scale_crop_aligned", New(testUUID).ScaleCrop(200, 100, "50p,20p"), "-/scale_crop/200x100/50p,20p/"},
+		{"smart_crop", New(testUUID).SmartCrop(300, 300), "-/scale_crop/300x300/smart/"},
+		{"crop", New(testUUID).Crop(100, 100, AlignCenter), "-/crop/100x100/center/"},
+		{"preview", New(testUUID).Preview(0, 0), "-/preview/"},
+		{"preview_size", New(testUUID).Preview(640, 480), "-/preview/640x480/"},
+		{"format_quality", New(testUUID).Format(FormatWebP).Quality(QualitySmartRetina), "-/format/webp/-/quality/smart_retina/"},
+		{"progressive", New(testUUID).Progressive(true), "-/progressive/yes/"},
+		{"rotate", New(testUUID).Rotate(270), "-/rotate/270/"},
+		{"flip_mirror", New(testUUID).Flip().Mirror().Grayscale(), "-/flip/-/mirror/-/grayscale/"},
+		{"blur", New(testUUID).Blur(0).Blur(100), "-/blur/-/blur/100/"},
+		{"sharpen", New(testUUID).Sharpen(10), "-/sharp/10/"},
+		{"overlay", New(testUUID).Overlay(OverlayOp{UUID: overlayUUID}), "-/overlay/" + overlayUUID + "/"},
+		{
+			"overlay_full",
+			New(testUUID).Overlay(OverlayOp{UUID: overlayUUID, Width: 50, Height: 40, Position: AlignBottom, Opacity: 80}),
+			"-/overlay/" + overlayUUID + "/50px40p/bottom/80p/",
+		},
+		{"setfill", New(testUUID).SetFill("ff00AA"), "-/setfill/ff00AA/"},
+		{"stretch", New(testUUID).Stretch(StretchFill), "-/stretch/fill/"},
+		{"strip_meta", New(testUUID).StripMeta(StripMetaSensitive), "-/strip_meta/sensitive/"},
+		{"filename", New(testUUID).Resize(100, 0).Filename("my photo.jpg"), "-/resize/100x/my%20photo.jpg"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := tc.b.Path()
+			require.NoError(t, err)
+			assert.Equal(t, testUUID+"/"+tc.want, p)
+		})
+	}
+}
+
+func TestBuilder_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		b       *Builder
+		wantErr string
+	}{
+		{"uuid", New("not-a-uuid"), `cdn: invalid file UUID: "not-a-uuid"`},
+		{"resize_empty", New(testUUID).Resize(0, 0), "cdn: invalid operation resize: width or height is required"},
+		{"resize_too_big", New(testUUID).Resize(6000, 0), "cdn: invalid operation resize: dimensions 6000x0 must be within 0..5000"},
+		{"scale_crop_one_dim", New(testUUID).ScaleCrop(100, 0), "cdn: invalid operation scale_crop: both width and height are required"},
+		{"crop_smart", New(testUUID).Crop(100, 100, AlignSmart), `cdn: invalid operation crop: smart alignment "smart" is not supported`},
+		{"alignment", New(testUUID).Crop(100, 100, "middle"), `cdn: invalid operation crop: invalid alignment "middle"`},
+		{"alignment_percent", New(testUUID).Crop(100, 100, "120p,0p"), `cdn: invalid operation crop: alignment "120p,0p" exceeds 100 percent`},
+		{"preview_one_dim", New(testUUID).Preview(100, 0), "cdn: invalid operation preview: both width and height are required"},
+		{"format", New(testUUID).Format("gif"), `cdn: invalid operation format: unsupported format "gif"`},
+		{"quality", New(testUUID).Quality("max"), `cdn: invalid operation quality: unsupported quality "max"`},
+		{"rotate", New(testUUID).Rotate(45), "cdn: invalid operation rotate: angle 45 must be one of 0, 90, 180 or 270"},
+		{"blur", New(testUUID).Blur(-1), "cdn: invalid operation blur: strength -1 must be within 0..5000"},
+		{"sharpen", New(testUUID).Sharpen(21), "cdn: invalid operation sharp: strength 21 must be within 0..20"},
+		{"overlay_uuid", New(testUUID).Overlay(OverlayOp{UUID: "x"}), `cdn: invalid operation overlay: invalid overlay UUID "x"`},
+		{"overlay_opacity", New(testUUID).Overlay(OverlayOp{UUID: overlayUUID, Width: 10, Height: 10, Opacity: 50}), "cdn: invalid operation overlay: opacity requires position"},
+		{"setfill", New(testUUID).SetFill("#fff"), `cdn: invalid operation setfill: color "#fff" must be 3 or 6 hex digits`},
+		{"stretch", New(testUUID).Stretch("maybe"), `cdn: invalid operation stretch: unsupported mode "maybe"`},
+		{"filename", New(testUUID).Filename("a/b.jpg"), `cdn: filename must not be empty or contain slashes: "a/b.jpg"`},
+		{"group_id", NewGroupFile("abc~2", 0), `cdn: invalid group ID: "abc~2"`},
+		{"group_nth", NewGroupFile(testUUID+"~2", -1), "cdn: group file index out of range: -1 is out of 0..1"},
+		{"first_error_wins", New(testUUID).Rotate(1).Blur(-1), "cdn: invalid operation rotate: angle 1 must be one of 0, 90, 180 or 270"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.b.Path()
+			assert.EqualError(t, err, tc.wantErr)
+			assert.Equal(t, err, tc.b.Err())
+		})
+	}
+
+	_, err := New(testUUID).Resize(0, 0).Path()
+	assert.ErrorIs(t, err, ErrInvalidOp)
+}
+
+func TestBuilder_Immutable(t *testing.T) {
+	t.Parallel()
+
+	base := New(testUUID).Format(FormatAuto)
+	small := base.Resize(100, 0)
+	large := base.Resize(1000, 0)
+
+	p, err := small.Path()
+	require.NoError(t, err)
+	assert.Equal(t, testUUID+"/-/format/auto/-/resize/100x/", p)
+	p, err = large.Path()
+	require.NoError(t, err)
+	assert.Equal(t, testUUID+"/-/format/auto/-/resize/1000x/", p)
+	assert.Len(t, base.Ops(), 1)
+}
+
+func TestBuilder_GroupFile(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewGroupFile(testUUID+"~3", 2).Resize(100, 0).Path()
+	require.NoError(t, err)
+	assert.Equal(t, testUUID+"~3/nth/2/-/resize/100x/", p)
+}
+
+func TestBuilder_URL(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://abc1234567.ucarecd.net/"}
+	u, err := New(testUUID).Preview(0, 0).URL(client)
+	require.NoError(t, err)
+	assert.Equal(t, "https://abc1234567.ucarecd.net/"+testUUID+"/-/preview/", u)
+
+	_, err = New(testUUID).URL(&uctest.Client{})
+	assert.ErrorIs(t, err, ErrNoCDNBase)
+}
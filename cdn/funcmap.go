@@ -0,0 +1,269 @@
+package cdn
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// ErrNoSigner is returned by the ucSigned template function when FuncMap
+// is given no signer
+var ErrNoSigner = errors.New("cdn: ucSigned requires a signer")
+
+// ErrSignedURL is returned by the template functions transforming a signed
+// image URL when FuncMap is given no signer to sign the result with
+var ErrSignedURL = errors.New("cdn: transforming a signed URL requires a signer")
+
+// ErrUntrustedURL is returned by the template functions for image URLs not
+// pointing at the client CDN base host
+var ErrUntrustedURL = errors.New("cdn: URL is not on the client CDN base host")
+
+// FuncMap returns template functions building CDN URLs. Every function
+// takes the image as its last argument, so the functions can be chained
+// in pipelines. The image is either a file UUID, resolved against the
+// client CDN base, or a CDN URL, e.g. returned by another function:
+//
+//	<img src="{{ .UUID | ucResize 800 0 | ucFormat "webp" }}"
+//	     srcset="{{ .UUID | ucFormat "auto" | ucSrcset "320,640,1280" }}">
+//
+// The functions are:
+//
+//	ucURL image                  the image URL
+//	ucResize width height image  see Builder.Resize
+//	ucScaleCrop width height image
+//	ucSmartCrop width height image
+//	ucPreview width height image
+//	ucFormat format image
+//	ucQuality quality image
+//	ucSrcset widths image        srcset of the image resized to the comma
+//	                             or space separated widths
+//	ucSigned image               the URL signed with the signer
+//
+// URLs are returned as template.URL and srcset as template.Srcset, so
+// html/template does not filter them out. For that reason image URLs are
+// only accepted with the http or https scheme and the host of the client
+// CDN base, other URLs fail the template execution with ErrUntrustedURL.
+// Invalid operations fail the template execution as well. The signer may
+// be nil if ucSigned is not used.
+//
+// The secure delivery token of a signed image URL does not cover the URL
+// with operations inserted, so the token is dropped and the result is
+// signed with the signer again. Without a signer, transforming a signed
+// URL fails the template execution with ErrSignedURL.
+//
+// The map suits text/template as well, convert it with
+// texttemplate.FuncMap(cdn.FuncMap(client, nil)).
+func FuncMap(client ucare.Client, signer *Signer) template.FuncMap {
+	f := funcs{base: ucare.ClientCDNBase(client), signer: signer}
+	return template.FuncMap{
+		"ucURL": func(image any) (template.URL, error) {
+			return f.apply(image, nil)
+		},
+		"ucResize": func(width, height int, image any) (template.URL, error) {
+			return f.apply(image, ResizeOp{Width: width, Height: height})
+		},
+		"ucScaleCrop": func(width, height int, image any) (template.URL, error) {
+			return f.apply(image, ScaleCropOp{Width: width, Height: height})
+		},
+		"ucSmartCrop": func(width, height int, image any) (template.URL, error) {
+			return f.apply(image, ScaleCropOp{Width: width, Height: height, Alignment: AlignSmart})
+		},
+		"ucPreview": func(width, height int, image any) (template.URL, error) {
+			return f.apply(image, PreviewOp{Width: width, Height: height})
+		},
+		"ucFormat": func(format string, image any) (template.URL, error) {
+			return f.apply(image, FormatOp{Format: ImageFormat(format)})
+		},
+		"ucQuality": func(quality string, image any) (template.URL, error) {
+			return f.apply(image, QualityOp{Quality: Quality(quality)})
+		},
+		"ucSrcset": f.srcset,
+		"ucSigned": f.signed,
+	}
+}
+
+type funcs struct {
+	base   string
+	signer *Signer
+}
+
+// parse returns the image URL with the CDN base set
+func (f funcs) parse(image any) (*URL, error) {
+	var s string
+	switch v := image.(type) {
+	case string:
+		s = v
+	case template.URL:
+		s = string(v)
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return nil, fmt.Errorf("cdn: unexpected image %T", image)
+	}
+
+	u := &URL{UUID: s}
+	if !uuidRe.MatchString(s) {
+		var err error
+		if u, err = Parse(s); err != nil {
+			return nil, err
+		}
+	}
+	if f.base == "" {
+		return nil, ErrNoCDNBase
+	}
+	if u.Base == "" {
+		u.Base = f.base
+		return u, nil
+	}
+	if !sameHost(u.Base, f.base) {
+		return nil, fmt.Errorf("%w: %q", ErrUntrustedURL, s)
+	}
+	return u, nil
+}
+
+// sameHost reports whether the http(s) URLs have the same host
+func sameHost(rawURL, base string) bool {
+	a, err := url.Parse(rawURL)
+	if err != nil || (a.Scheme != "http" && a.Scheme != "https") {
+		return false
+	}
+	b, err := url.Parse(base)
+	if err != nil || b.Host == "" {
+		return false
+	}
+	return strings.EqualFold(a.Host, b.Host)
+}
+
+func (f funcs) builder(image any, op Op) (*URL, *Builder, error) {
+	u, err := f.parse(image)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := u.Builder()
+	if op != nil {
+		b = b.Op(op)
+	}
+	return u, b, b.Err()
+}
+
+func (f funcs) apply(image any, op Op) (template.URL, error) {
+	u, b, err := f.builder(image, op)
+	if err != nil {
+		return "", err
+	}
+	var s string
+	if op != nil && unsign(u) {
+		if f.signer == nil {
+			return "", ErrSignedURL
+		}
+		s, err = b.signedURL(u.Base, f.signer)
+	} else {
+		s, err = b.URLWithBase(u.Base)
+	}
+	return template.URL(withQuery(s, u.RawQuery)), err
+}
+
+// unsign drops the secure delivery token from the URL and reports whether
+// the URL has been signed
+func unsign(u *URL) bool {
+	signed := false
+	if u.RawQuery != "" {
+		var kept []string
+		for _, kv := range strings.Split(u.RawQuery, "&") {
+			if strings.HasPrefix(kv, TokenParam+"=") {
+				signed = true
+				continue
+			}
+			kept = append(kept, kv)
+		}
+		u.RawQuery = strings.Join(kept, "&")
+	}
+
+	// the token embedded into the path is parsed as a part of the base
+	prefix := "/" + TokenParam + "="
+	if i := strings.Index(u.Base, prefix); i >= 0 {
+		rest := ""
+		if j := strings.Index(u.Base[i+len(prefix):], "/"); j >= 0 {
+			rest = u.Base[i+len(prefix)+j:]
+		}
+		u.Base = u.Base[:i] + rest
+		signed = true
+	}
+	return signed
+}
+
+// withQuery appends the query to the URL which may have one already
+func withQuery(rawURL, query string) string {
+	switch {
+	case query == "":
+		return rawURL
+	case strings.Contains(rawURL, "?"):
+		return rawURL + "&" + query
+	}
+	return rawURL + "?" + query
+}
+
+func (f funcs) srcset(widths any, image any) (template.Srcset, error) {
+	var ws []int
+	switch v := widths.(type) {
+	case []int:
+		ws = v
+	case int:
+		ws = []int{v}
+	case string:
+		for _, w := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(w)
+			if err != nil {
+				return "", fmt.Errorf("cdn: invalid srcset width %q", w)
+			}
+			ws = append(ws, n)
+		}
+	default:
+		return "", fmt.Errorf("cdn: unexpected srcset widths %T", widths)
+	}
+
+	u, b, err := f.builder(image, nil)
+	if err != nil {
+		return "", err
+	}
+	ws = SrcsetParams{Widths: ws}.widths()
+	if !unsign(u) {
+		s, err := b.srcset(u.Base, ws, "")
+		return template.Srcset(s), err
+	}
+
+	if f.signer == nil {
+		return "", ErrSignedURL
+	}
+	if len(ws) == 0 {
+		return "", ErrNoWidths
+	}
+	candidates := make([]string, len(ws))
+	for i, w := range ws {
+		s, err := b.variant(w, "").signedURL(u.Base, f.signer)
+		if err != nil {
+			return "", err
+		}
+		candidates[i] = s + " " + strconv.Itoa(w) + "w"
+	}
+	return template.Srcset(strings.Join(candidates, ", ")), nil
+}
+
+func (f funcs) signed(image any) (template.URL, error) {
+	if f.signer == nil {
+		return "", ErrNoSigner
+	}
+	u, b, err := f.builder(image, nil)
+	if err != nil {
+		return "", err
+	}
+	// the token is replaced rather than added next to the old one
+	unsign(u)
+	s, err := b.signedURL(u.Base, f.signer)
+	return template.URL(s), err
+}
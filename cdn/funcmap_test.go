@@ -0,0 +1,156 @@
+package cdn
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://cdn.example.com"}
+	signer := testSigner(t, TokenQuery)
+	base := "https://cdn.example.com/" + testUUID
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"url", `<a href="{{ ucURL . }}">`, `<a href="` + base + `/">`},
+		{
+			"pipeline",
+			`<img src="{{ . | ucResize 800 0 | ucFormat "webp" | ucQuality "smart" }}">`,
+			`<img src="` + base + `/-/resize/800x/-/format/webp/-/quality/smart/">`,
+		},
+		{
+			"crops",
+			`{{ . | ucSmartCrop 100 100 }} {{ . | ucScaleCrop 10 20 }} {{ . | ucPreview 0 0 }}`,
+			base + "/-/scale_crop/100x100/smart/ " + base + "/-/scale_crop/10x20/ " + base + "/-/preview/",
+		},
+		{
+			"srcset",
+			`<img srcset="{{ . | ucFormat "auto" | ucSrcset "640, 320" }}">`,
+			`<img srcset="` + base + `/-/resize/320x/-/format/auto/ 320w, ` + base + `/-/resize/640x/-/format/auto/ 640w">`,
+		},
+		{
+			"cdn_url",
+			`{{ "http://CDN.example.com/` + testUUID + `/photo.jpg" | ucResize 0 100 }}`,
+			"http://CDN.example.com/" + testUUID + "/-/resize/x100/photo.jpg",
+		},
+		{
+			"signed",
+			`{{ ucSigned . }}`,
+			base + "/?token=exp=1717243200~acl=/" + testUUID + "/*~hmac=b6c39715ccb6ed9cd75f413a9053a4a1b7819ae618bf6605765210481e3ae180",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := template.New("").Funcs(FuncMap(client, signer)).Parse(tc.tmpl)
+			require.NoError(t, err)
+			var out strings.Builder
+			require.NoError(t, tmpl.Execute(&out, testUUID))
+			assert.Equal(t, tc.want, out.String())
+		})
+	}
+}
+
+func TestFuncMap_Errors(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://cdn.example.com"}
+	tests := []struct {
+		tmpl    string
+		wantErr string
+	}{
+		{`{{ . | ucFormat "gif" }}`, `cdn: invalid operation format: unsupported format "gif"`},
+		{`{{ "nope" | ucURL }}`, `cdn: malformed CDN URL "nope": no file UUID or group ID`},
+		{`{{ . | ucSrcset "a" }}`, `cdn: invalid srcset width "a"`},
+		{`{{ ucSigned . }}`, "cdn: ucSigned requires a signer"},
+		{`{{ "javascript://x/` + testUUID + `/%0aalert(document.domain)" | ucResize 100 0 }}`, `unsupported scheme "javascript"`},
+		{`{{ "data:text/html,` + testUUID + `/" | ucURL }}`, "opaque URLs are not supported"},
+		{`{{ "https://evil.example.org/` + testUUID + `/" | ucURL }}`, "cdn: URL is not on the client CDN base host"},
+		{`{{ "https://evil.example.org/` + testUUID + `/" | ucSrcset 320 }}`, "cdn: URL is not on the client CDN base host"},
+	}
+	for _, tc := range tests {
+		tmpl, err := template.New("").Funcs(FuncMap(client, nil)).Parse(tc.tmpl)
+		require.NoError(t, err)
+		err = tmpl.Execute(&strings.Builder{}, testUUID)
+		require.Error(t, err, tc.tmpl)
+		assert.Contains(t, err.Error(), tc.wantErr)
+	}
+}
+
+func TestFuncMap_SignedInput(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://cdn.example.com"}
+	execute := func(t *testing.T, signer *Signer, tmpl, image string) (string, error) {
+		t.Helper()
+		// text/template keeps the URLs unescaped for verification
+		parsed, err := texttemplate.New("").Funcs(texttemplate.FuncMap(FuncMap(client, signer))).Parse(tmpl)
+		require.NoError(t, err)
+		var out strings.Builder
+		err = parsed.Execute(&out, image)
+		return out.String(), err
+	}
+
+	for _, form := range []TokenForm{TokenQuery, TokenPath} {
+		signer := testSigner(t, form)
+		// the ACL of the signed URL ends with the file name
+		signed, err := signer.Sign("https://cdn.example.com/" + testUUID + "/photo.jpg?v=1")
+		require.NoError(t, err)
+		require.NoError(t, signer.Verify(signed))
+
+		got, err := execute(t, signer, `{{ ucURL . }}`, signed)
+		require.NoError(t, err)
+		assert.Equal(t, signed, got, "the URL must be passed as is")
+
+		got, err = execute(t, signer, `{{ . | ucResize 100 0 }}`, signed)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(got, TokenParam+"="), got)
+		assert.Contains(t, got, "/-/resize/100x/photo.jpg")
+		assert.Contains(t, got, "v=1")
+		assert.NoError(t, signer.Verify(got), got)
+
+		got, err = execute(t, signer, `{{ ucSigned . }}`, signed)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(got, TokenParam+"="), got)
+		assert.NoError(t, signer.Verify(got), got)
+
+		got, err = execute(t, signer, `{{ . | ucSrcset "320,640" }}`, signed)
+		require.NoError(t, err)
+		candidates := strings.Split(got, ", ")
+		require.Len(t, candidates, 2)
+		for _, c := range candidates {
+			rawURL, _, _ := strings.Cut(c, " ")
+			assert.NoError(t, signer.Verify(rawURL), rawURL)
+		}
+
+		_, err = execute(t, nil, `{{ . | ucResize 100 0 }}`, signed)
+		assert.ErrorIs(t, err, ErrSignedURL)
+		_, err = execute(t, nil, `{{ . | ucSrcset 320 }}`, signed)
+		assert.ErrorIs(t, err, ErrSignedURL)
+	}
+}
+
+func TestFuncMap_TextTemplate(t *testing.T) {
+	t.Parallel()
+
+	funcs := texttemplate.FuncMap(FuncMap(&uctest.Client{CDN: "https://cdn.example.com"}, nil))
+	tmpl, err := texttemplate.New("").Funcs(funcs).Parse(`{{ . | ucResize 10 10 }}`)
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.NoError(t, tmpl.Execute(&out, testUUID))
+	assert.Equal(t, "https://cdn.example.com/"+testUUID+"/-/resize/10x10/", out.String())
+}
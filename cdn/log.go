@@ -0,0 +1,20 @@
+package cdn
+
+import (
+	"github.com/uploadcare/uploadcare-go/v2/uclog"
+)
+
+var log uclog.Logger
+
+const subsystemTag = "CDNS"
+
+func init() { DisableLog() }
+
+// DisableLog does what you expect
+func DisableLog() { log = uclog.Disabled }
+
+// EnableLog enables package scoped logging
+func EnableLog(lvl uclog.Level) {
+	log = uclog.Backend.Logger(subsystemTag)
+	log.SetLevel(lvl)
+}
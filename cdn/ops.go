@@ -0,0 +1,518 @@
+package cdn
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxDimension is the maximum image width or height accepted by the
+// resizing operations
+const MaxDimension = 5000
+
+// ErrInvalidOp is wrapped by the errors of the operations failing
+// validation
+var ErrInvalidOp = errors.New("invalid operation")
+
+// OpError describes an invalid operation
+type OpError struct {
+	// Op is the operation name
+	Op     string
+	Reason string
+}
+
+func (e *OpError) Error() string {
+	return fmt.Sprintf("cdn: %s %s: %s", ErrInvalidOp, e.Op, e.Reason)
+}
+
+// Unwrap returns ErrInvalidOp
+func (e *OpError) Unwrap() error { return ErrInvalidOp }
+
+func opErr(op, format string, args ...any) error {
+	return &OpError{Op: op, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Op is an image processing operation applied by the CDN. It is one of
+// the *Op types of the package.
+type Op interface {
+	// Name returns the operation name as it appears in URLs
+	Name() string
+	// Args returns the operation arguments as they appear in URLs
+	Args() []string
+
+	validate() error
+}
+
+// Alignment positions a cropped area or an overlay. It is one of the
+// Align constants, "X,Y" offset in pixels or "Xp,Yp" offset in percents.
+// The smart crop types are only accepted by ScaleCropOp.
+type Alignment string
+
+// Alignment keywords
+const (
+	AlignCenter Alignment = "center"
+	AlignTop    Alignment = "top"
+	AlignBottom Alignment = "bottom"
+	AlignLeft   Alignment = "left"
+	AlignRight  Alignment = "right"
+)
+
+// Smart crop types
+const (
+	AlignSmart              Alignment = "smart"
+	AlignSmartFaces         Alignment = "smart_faces"
+	AlignSmartObjects       Alignment = "smart_objects"
+	AlignSmartFacesObjects  Alignment = "smart_faces_objects"
+	AlignSmartFacesPoints   Alignment = "smart_faces_points"
+	AlignSmartObjectsPoints Alignment = "smart_objects_points"
+	AlignSmartPoints        Alignment = "smart_points"
+)
+
+var alignOffsetRe = regexp.MustCompile(`^\d+p?,\d+p?$`)
+
+func (a Alignment) validate(op string, smart bool) error {
+	switch a {
+	case AlignCenter, AlignTop, AlignBottom, AlignLeft, AlignRight:
+		return nil
+	case AlignSmart, AlignSmartFaces, AlignSmartObjects, AlignSmartFacesObjects,
+		AlignSmartFacesPoints, AlignSmartObjectsPoints, AlignSmartPoints:
+		if smart {
+			return nil
+		}
+		return opErr(op, "smart alignment %q is not supported", a)
+	}
+	if !alignOffsetRe.MatchString(string(a)) {
+		return opErr(op, "invalid alignment %q", a)
+	}
+	for _, v := range strings.Split(string(a), ",") {
+		if n, _ := strconv.Atoi(strings.TrimSuffix(v, "p")); strings.HasSuffix(v, "p") && n > 100 {
+			return opErr(op, "alignment %q exceeds 100 percent", a)
+		}
+	}
+	return nil
+}
+
+func dims(w, h int) string {
+	var b strings.Builder
+	if w > 0 {
+		b.WriteString(strconv.Itoa(w))
+	}
+	b.WriteByte('x')
+	if h > 0 {
+		b.WriteString(strconv.Itoa(h))
+	}
+	return b.String()
+}
+
+func validateDims(op string, w, h int, both bool) error {
+	if w < 0 || h < 0 || w > MaxDimension || h > MaxDimension {
+		return opErr(op, "dimensions %dx%d must be within 0..%d", w, h, MaxDimension)
+	}
+	if both && (w == 0 || h == 0) {
+		return opErr(op, "both width and height are required")
+	}
+	if w == 0 && h == 0 {
+		return opErr(op, "width or height is required")
+	}
+	return nil
+}
+
+// ResizeOp resizes the image preserving its aspect ratio when only one of
+// the dimensions is set
+type ResizeOp struct {
+	Width, Height int
+}
+
+func (op ResizeOp) Name() string    { return "resize" }
+func (op ResizeOp) Args() []string  { return []string{dims(op.Width, op.Height)} }
+func (op ResizeOp) validate() error { return validateDims(op.Name(), op.Width, op.Height, false) }
+
+// ScaleCropOp scales the image down to cover the dimensions and crops it
+// according to the alignment, the center by default
+type ScaleCropOp struct {
+	Width, Height int
+	Alignment     Alignment
+}
+
+func (op ScaleCropOp) Name() string { return "scale_crop" }
+
+func (op ScaleCropOp) Args() []string {
+	return withAlignment([]string{dims(op.Width, op.Height)}, op.Alignment)
+}
+
+func (op ScaleCropOp) validate() error {
+	if err := validateDims(op.Name(), op.Width, op.Height, true); err != nil {
+		return err
+	}
+	if op.Alignment == "" {
+		return nil
+	}
+	return op.Alignment.validate(op.Name(), true)
+}
+
+// CropOp crops the area of the dimensions positioned according to the
+// alignment, the top left corner by default
+type CropOp struct {
+	Width, Height int
+	Alignment     Alignment
+}
+
+func (op CropOp) Name() string { return "crop" }
+
+func (op CropOp) Args() []string {
+	return withAlignment([]string{dims(op.Width, op.Height)}, op.Alignment)
+}
+
+func (op CropOp) validate() error {
+	if err := validateDims(op.Name(), op.Width, op.Height, true); err != nil {
+		return err
+	}
+	if op.Alignment == "" {
+		return nil
+	}
+	return op.Alignment.validate(op.Name(), false)
+}
+
+func withAlignment(args []string, a Alignment) []string {
+	if a != "" {
+		args = append(args, string(a))
+	}
+	return args
+}
+
+// PreviewOp downscales the image to fit the dimensions. Without the
+// dimensions the image is fit into 2048x2048.
+type PreviewOp struct {
+	Width, Height int
+}
+
+func (op PreviewOp) Name() string { return "preview" }
+
+func (op PreviewOp) Args() []string {
+	if op.Width == 0 && op.Height == 0 {
+		return nil
+	}
+	return []string{dims(op.Width, op.Height)}
+}
+
+func (op PreviewOp) validate() error {
+	if op.Width == 0 && op.Height == 0 {
+		return nil
+	}
+	return validateDims(op.Name(), op.Width, op.Height, true)
+}
+
+// ImageFormat is an image output format
+type ImageFormat string
+
+// Image output formats
+const (
+	FormatJPEG     ImageFormat = "jpeg"
+	FormatPNG      ImageFormat = "png"
+	FormatWebP     ImageFormat = "webp"
+	FormatAuto     ImageFormat = "auto"
+	FormatPreserve ImageFormat = "preserve"
+)
+
+// FormatOp converts the image to the format
+type FormatOp struct {
+	Format ImageFormat
+}
+
+func (op FormatOp) Name() string   { return "format" }
+func (op FormatOp) Args() []string { return []string{string(op.Format)} }
+
+func (op FormatOp) validate() error {
+	switch op.Format {
+	case FormatJPEG, FormatPNG, FormatWebP, FormatAuto, FormatPreserve:
+		return nil
+	}
+	return opErr(op.Name(), "unsupported format %q", op.Format)
+}
+
+// Quality is an image compression quality level
+type Quality string
+
+// Image compression quality levels
+const (
+	QualityNormal      Quality = "normal"
+	QualityBetter      Quality = "better"
+	QualityBest        Quality = "best"
+	QualityLighter     Quality = "lighter"
+	QualityLightest    Quality = "lightest"
+	QualitySmart       Quality = "smart"
+	QualitySmartRetina Quality = "smart_retina"
+)
+
+// QualityOp sets the compression quality of the image
+type QualityOp struct {
+	Quality Quality
+}
+
+func (op QualityOp) Name() string   { return "quality" }
+func (op QualityOp) Args() []string { return []string{string(op.Quality)} }
+
+func (op QualityOp) validate() error {
+	switch op.Quality {
+	case QualityNormal, QualityBetter, QualityBest, QualityLighter,
+		QualityLightest, QualitySmart, QualitySmartRetina:
+		return nil
+	}
+	return opErr(op.Name(), "unsupported quality %q", op.Quality)
+}
+
+// ProgressiveOp turns progressive JPEG encoding on or off
+type ProgressiveOp struct {
+	Enabled bool
+}
+
+func (op ProgressiveOp) Name() string    { return "progressive" }
+func (op ProgressiveOp) Args() []string  { return []string{yesNo(op.Enabled)} }
+func (op ProgressiveOp) validate() error { return nil }
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// RotateOp rotates the image counterclockwise by the angle, a multiple
+// of 90 degrees
+type RotateOp struct {
+	Angle int
+}
+
+func (op RotateOp) Name() string   { return "rotate" }
+func (op RotateOp) Args() []string { return []string{strconv.Itoa(op.Angle)} }
+
+func (op RotateOp) validate() error {
+	if op.Angle < 0 || op.Angle >= 360 || op.Angle%90 != 0 {
+		return opErr(op.Name(), "angle %d must be one of 0, 90, 180 or 270", op.Angle)
+	}
+	return nil
+}
+
+// FlipOp flips the image vertically
+type FlipOp struct{}
+
+func (op FlipOp) Name() string    { return "flip" }
+func (op FlipOp) Args() []string  { return nil }
+func (op FlipOp) validate() error { return nil }
+
+// MirrorOp flips the image horizontally
+type MirrorOp struct{}
+
+func (op MirrorOp) Name() string    { return "mirror" }
+func (op MirrorOp) Args() []string  { return nil }
+func (op MirrorOp) validate() error { return nil }
+
+// GrayscaleOp desaturates the image
+type GrayscaleOp struct{}
+
+func (op GrayscaleOp) Name() string    { return "grayscale" }
+func (op GrayscaleOp) Args() []string  { return nil }
+func (op GrayscaleOp) validate() error { return nil }
+
+// MaxBlurStrength is the maximum BlurOp strength
+const MaxBlurStrength = 5000
+
+// BlurOp blurs the image. Zero strength applies the CDN default one.
+type BlurOp struct {
+	Strength int
+}
+
+func (op BlurOp) Name() string   { return "blur" }
+func (op BlurOp) Args() []string { return optionalInt(op.Strength) }
+
+func (op BlurOp) validate() error {
+	if op.Strength < 0 || op.Strength > MaxBlurStrength {
+		return opErr(op.Name(), "strength %d must be within 0..%d", op.Strength, MaxBlurStrength)
+	}
+	return nil
+}
+
+// MaxSharpenStrength is the maximum SharpenOp strength
+const MaxSharpenStrength = 20
+
+// SharpenOp sharpens the image. Zero strength applies the CDN default one.
+type SharpenOp struct {
+	Strength int
+}
+
+func (op SharpenOp) Name() string   { return "sharp" }
+func (op SharpenOp) Args() []string { return optionalInt(op.Strength) }
+
+func (op SharpenOp) validate() error {
+	if op.Strength < 0 || op.Strength > MaxSharpenStrength {
+		return opErr(op.Name(), "strength %d must be within 0..%d", op.Strength, MaxSharpenStrength)
+	}
+	return nil
+}
+
+func optionalInt(v int) []string {
+	if v == 0 {
+		return nil
+	}
+	return []string{strconv.Itoa(v)}
+}
+
+// OverlayOp puts another image over the image. The size, position and
+// opacity are optional, but each of them requires the preceding ones.
+type OverlayOp struct {
+	// UUID is the ID of the overlaid image
+	UUID string
+
+	// Width and Height are the overlay size in percents of the image size
+	Width, Height int
+
+	// Position is the overlay position
+	Position Alignment
+
+	// Opacity is the overlay opacity in percents
+	Opacity int
+}
+
+func (op OverlayOp) Name() string { return "overlay" }
+
+func (op OverlayOp) Args() []string {
+	args := []string{op.UUID}
+	if op.Width == 0 && op.Height == 0 {
+		return args
+	}
+	args = append(args, fmt.Sprintf("%dpx%dp", op.Width, op.Height))
+	if op.Position == "" {
+		return args
+	}
+	args = append(args, string(op.Position))
+	if op.Opacity == 0 {
+		return args
+	}
+	return append(args, fmt.Sprintf("%dp", op.Opacity))
+}
+
+func (op OverlayOp) validate() error {
+	if !uuidRe.MatchString(op.UUID) {
+		return opErr(op.Name(), "invalid overlay UUID %q", op.UUID)
+	}
+	if op.Width < 0 || op.Height < 0 || op.Width > 100 || op.Height > 100 {
+		return opErr(op.Name(), "size %dx%d must be within 0..100 percent", op.Width, op.Height)
+	}
+	if (op.Width == 0) != (op.Height == 0) {
+		return opErr(op.Name(), "both width and height are required")
+	}
+	if op.Position != "" {
+		if op.Width == 0 {
+			return opErr(op.Name(), "position requires size")
+		}
+		if err := op.Position.validate(op.Name(), false); err != nil {
+			return err
+		}
+	}
+	if op.Opacity < 0 || op.Opacity > 100 {
+		return opErr(op.Name(), "opacity %d must be within 0..100 percent", op.Opacity)
+	}
+	if op.Opacity != 0 && op.Position == "" {
+		return opErr(op.Name(), "opacity requires position")
+	}
+	return nil
+}
+
+var hexColorRe = regexp.MustCompile(`^([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// SetFillOp sets the color of the background filling the transparent and
+// padded areas, e.g. "ffffff"
+type SetFillOp struct {
+	Color string
+}
+
+func (op SetFillOp) Name() string   { return "setfill" }
+func (op SetFillOp) Args() []string { return []string{op.Color} }
+
+func (op SetFillOp) validate() error {
+	if !hexColorRe.MatchString(op.Color) {
+		return opErr(op.Name(), "color %q must be 3 or 6 hex digits", op.Color)
+	}
+	return nil
+}
+
+// StretchMode tells how images smaller than the requested size are resized
+type StretchMode string
+
+// Stretch modes
+const (
+	StretchOn   StretchMode = "on"
+	StretchOff  StretchMode = "off"
+	StretchFill StretchMode = "fill"
+)
+
+// StretchOp sets the stretch mode of the resizing operations
+type StretchOp struct {
+	Mode StretchMode
+}
+
+func (op StretchOp) Name() string   { return "stretch" }
+func (op StretchOp) Args() []string { return []string{string(op.Mode)} }
+
+func (op StretchOp) validate() error {
+	switch op.Mode {
+	case StretchOn, StretchOff, StretchFill:
+		return nil
+	}
+	return opErr(op.Name(), "unsupported mode %q", op.Mode)
+}
+
+// StripMetaMode tells which image metadata is kept
+type StripMetaMode string
+
+// Image metadata strip modes
+const (
+	StripMetaAll       StripMetaMode = "all"
+	StripMetaNone      StripMetaMode = "none"
+	StripMetaSensitive StripMetaMode = "sensitive"
+)
+
+// StripMetaOp strips the image metadata
+type StripMetaOp struct {
+	Mode StripMetaMode
+}
+
+func (op StripMetaOp) Name() string   { return "strip_meta" }
+func (op StripMetaOp) Args() []string { return []string{string(op.Mode)} }
+
+func (op StripMetaOp) validate() error {
+	switch op.Mode {
+	case StripMetaAll, StripMetaNone, StripMetaSensitive:
+		return nil
+	}
+	return opErr(op.Name(), "unsupported mode %q", op.Mode)
+}
+
+var (
+	rawOpNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	rawOpArgRe  = regexp.MustCompile(`^[\w.,:~%-]+$`)
+)
+
+// RawOp is an operation the package has no type for, e.g. "enhance/50".
+// Parse returns it for unknown operations, so they survive the round trip.
+type RawOp struct {
+	OpName string
+	OpArgs []string
+}
+
+func (op RawOp) Name() string   { return op.OpName }
+func (op RawOp) Args() []string { return op.OpArgs }
+
+func (op RawOp) validate() error {
+	if !rawOpNameRe.MatchString(op.OpName) {
+		return opErr(op.OpName, "invalid operation name")
+	}
+	for _, arg := range op.OpArgs {
+		if !rawOpArgRe.MatchString(arg) {
+			return opErr(op.OpName, "invalid argument %q", arg)
+		}
+	}
+	return nil
+}
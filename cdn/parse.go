@@ -0,0 +1,327 @@
+package cdn
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrMalformedURL is wrapped by the Parse errors of URLs not being CDN
+// file URLs
+var ErrMalformedURL = errors.New("malformed CDN URL")
+
+// URL is a parsed CDN file URL
+type URL struct {
+	// Base is the URL part preceding the file ID, e.g.
+	// "https://ucarecdn.com". It is empty for relative URLs.
+	Base string
+
+	// UUID is the file ID, empty for group files
+	UUID string
+
+	// GroupID is the group ID for group files, e.g. "uuid~3"
+	GroupID string
+
+	// Nth is the index of the group file
+	Nth int
+
+	// Ops are the operations in the order they are applied
+	Ops []Op
+
+	// Filename is the trailing file name, if any
+	Filename string
+
+	// RawQuery is the URL query without the leading "?", if any
+	RawQuery string
+}
+
+// Builder returns a builder producing the URL
+func (u *URL) Builder() *Builder {
+	var b *Builder
+	if u.GroupID != "" {
+		b = NewGroupFile(u.GroupID, u.Nth)
+	} else {
+		b = New(u.UUID)
+	}
+	for _, op := range u.Ops {
+		b = b.Op(op)
+	}
+	if u.Filename != "" {
+		b = b.Filename(u.Filename)
+	}
+	return b
+}
+
+// String reassembles the URL
+func (u *URL) String() string {
+	p, err := u.Builder().Path()
+	if err != nil {
+		return ""
+	}
+	s := p
+	if u.Base != "" {
+		s = strings.TrimRight(u.Base, "/") + "/" + p
+	}
+	if u.RawQuery != "" {
+		s += "?" + u.RawQuery
+	}
+	return s
+}
+
+// Parse parses an absolute CDN file URL, e.g.
+// "https://ucarecdn.com/uuid/-/resize/800x/photo.jpg", or a relative one,
+// e.g. "uuid/-/resize/800x/" as accepted by file.LocalCopyParams.Source.
+// Group file URLs, e.g. "https://ucarecdn.com/uuid~3/nth/0/", are parsed as
+// well. Operations unknown to the package are returned as RawOp.
+// Absolute URLs must use the http or https scheme.
+//
+// The returned errors wrap either ErrMalformedURL or ErrInvalidOp.
+func Parse(rawURL string) (*URL, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, malformed(rawURL, "%s", err)
+	}
+	if parsed.Opaque != "" {
+		return nil, malformed(rawURL, "opaque URLs are not supported")
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		if parsed.Host == "" {
+			return nil, malformed(rawURL, "missing host")
+		}
+	case "":
+		if parsed.Host != "" {
+			return nil, malformed(rawURL, "missing scheme")
+		}
+	default:
+		return nil, malformed(rawURL, "unsupported scheme %q", parsed.Scheme)
+	}
+
+	u := URL{RawQuery: parsed.RawQuery}
+	segs := strings.Split(parsed.EscapedPath(), "/")
+
+	// the file ID may follow a path prefix of a custom CDN base
+	start := -1
+	for i, seg := range segs {
+		if uuidRe.MatchString(seg) || groupIDRe.MatchString(seg) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, malformed(rawURL, "no file UUID or group ID")
+	}
+	if parsed.Host != "" {
+		prefix := strings.Join(segs[:start], "/")
+		u.Base = strings.TrimRight(parsed.Scheme+"://"+parsed.Host+prefix, "/")
+	} else if start > 0 && segs[start-1] != "" {
+		return nil, malformed(rawURL, "unexpected path prefix %q", strings.Join(segs[:start], "/"))
+	}
+	segs = segs[start:]
+
+	if groupIDRe.MatchString(segs[0]) {
+		u.GroupID = segs[0]
+		if len(segs) < 4 || segs[1] != "nth" {
+			return nil, malformed(rawURL, "group URL must point at a file with /nth/<index>/")
+		}
+		if u.Nth, err = strconv.Atoi(segs[2]); err != nil {
+			return nil, malformed(rawURL, "invalid group file index %q", segs[2])
+		}
+		segs = segs[2:]
+	} else {
+		u.UUID = segs[0]
+	}
+
+	// the segments are followed by slashes except for the file name
+	last := len(segs) - 1
+	if last == 0 {
+		return nil, malformed(rawURL, "missing slash after %q", segs[0])
+	}
+	if segs[last] != "" {
+		if u.Filename, err = url.PathUnescape(segs[last]); err != nil {
+			return nil, malformed(rawURL, "invalid file name %q", segs[last])
+		}
+	}
+	segs = segs[1:last]
+
+	for len(segs) > 0 {
+		if segs[0] != "-" {
+			return nil, malformed(rawURL, `expected "-" before operation, got %q`, segs[0])
+		}
+		if len(segs) < 2 || segs[1] == "" || segs[1] == "-" {
+			return nil, malformed(rawURL, `missing operation name after "-"`)
+		}
+		name := segs[1]
+		end := 2
+		for end < len(segs) && segs[end] != "-" {
+			end++
+		}
+		op, err := parseOp(name, segs[2:end])
+		if err != nil {
+			return nil, err
+		}
+		if err := op.validate(); err != nil {
+			return nil, err
+		}
+		u.Ops = append(u.Ops, op)
+		segs = segs[end:]
+	}
+
+	if err := u.Builder().Err(); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func malformed(rawURL, format string, args ...any) error {
+	return fmt.Errorf("cdn: %w %q: %s", ErrMalformedURL, rawURL, fmt.Sprintf(format, args...))
+}
+
+func parseOp(name string, args []string) (Op, error) {
+	nargs := func(min, max int) error {
+		if len(args) < min || len(args) > max {
+			if min == max && min == 1 {
+				return opErr(name, "expected 1 argument, got %d", len(args))
+			}
+			if min == max {
+				return opErr(name, "expected %d arguments, got %d", min, len(args))
+			}
+			return opErr(name, "expected %d to %d arguments, got %d", min, max, len(args))
+		}
+		return nil
+	}
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+
+	switch name {
+	case "resize":
+		if err := nargs(1, 1); err != nil {
+			return nil, err
+		}
+		w, h, err := parseDims(name, args[0])
+		return ResizeOp{Width: w, Height: h}, err
+	case "scale_crop", "crop":
+		if err := nargs(1, 2); err != nil {
+			return nil, err
+		}
+		w, h, err := parseDims(name, args[0])
+		if name == "crop" {
+			return CropOp{Width: w, Height: h, Alignment: Alignment(arg(1))}, err
+		}
+		return ScaleCropOp{Width: w, Height: h, Alignment: Alignment(arg(1))}, err
+	case "preview":
+		if err := nargs(0, 1); err != nil || len(args) == 0 {
+			return PreviewOp{}, err
+		}
+		w, h, err := parseDims(name, args[0])
+		return PreviewOp{Width: w, Height: h}, err
+	case "format":
+		return FormatOp{Format: ImageFormat(arg(0))}, nargs(1, 1)
+	case "quality":
+		return QualityOp{Quality: Quality(arg(0))}, nargs(1, 1)
+	case "stretch":
+		return StretchOp{Mode: StretchMode(arg(0))}, nargs(1, 1)
+	case "strip_meta":
+		return StripMetaOp{Mode: StripMetaMode(arg(0))}, nargs(1, 1)
+	case "setfill":
+		return SetFillOp{Color: arg(0)}, nargs(1, 1)
+	case "progressive":
+		if err := nargs(1, 1); err != nil {
+			return nil, err
+		}
+		switch args[0] {
+		case "yes":
+			return ProgressiveOp{Enabled: true}, nil
+		case "no":
+			return ProgressiveOp{}, nil
+		}
+		return nil, opErr(name, `expected "yes" or "no", got %q`, args[0])
+	case "rotate":
+		if err := nargs(1, 1); err != nil {
+			return nil, err
+		}
+		angle, err := parseInt(name, args[0])
+		return RotateOp{Angle: angle}, err
+	case "flip":
+		return FlipOp{}, nargs(0, 0)
+	case "mirror":
+		return MirrorOp{}, nargs(0, 0)
+	case "grayscale":
+		return GrayscaleOp{}, nargs(0, 0)
+	case "blur", "sharp":
+		if err := nargs(0, 1); err != nil {
+			return nil, err
+		}
+		var strength int
+		if len(args) == 1 {
+			var err error
+			if strength, err = parseInt(name, args[0]); err != nil {
+				return nil, err
+			}
+		}
+		if name == "blur" {
+			return BlurOp{Strength: strength}, nil
+		}
+		return SharpenOp{Strength: strength}, nil
+	case "overlay":
+		return parseOverlay(args, nargs(1, 4))
+	}
+	return RawOp{OpName: name, OpArgs: args}, nil
+}
+
+var (
+	dimsRe        = regexp.MustCompile(`^(\d*)x(\d*)$`)
+	overlaySizeRe = regexp.MustCompile(`^(\d+)px(\d+)p$`)
+	percentRe     = regexp.MustCompile(`^(\d+)p$`)
+)
+
+func parseDims(op, s string) (w, h int, err error) {
+	m := dimsRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, opErr(op, "invalid dimensions %q", s)
+	}
+	w, _ = strconv.Atoi(m[1])
+	h, _ = strconv.Atoi(m[2])
+	return w, h, nil
+}
+
+func parseInt(op, s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, opErr(op, "invalid number %q", s)
+	}
+	return v, nil
+}
+
+func parseOverlay(args []string, err error) (Op, error) {
+	if err != nil {
+		return nil, err
+	}
+	op := OverlayOp{UUID: args[0]}
+	if len(args) > 1 {
+		m := overlaySizeRe.FindStringSubmatch(args[1])
+		if m == nil {
+			return nil, opErr(op.Name(), "invalid size %q", args[1])
+		}
+		op.Width, _ = strconv.Atoi(m[1])
+		op.Height, _ = strconv.Atoi(m[2])
+	}
+	if len(args) > 2 {
+		op.Position = Alignment(args[2])
+	}
+	if len(args) > 3 {
+		m := percentRe.FindStringSubmatch(args[3])
+		if m == nil {
+			return nil, opErr(op.Name(), "invalid opacity %q", args[3])
+		}
+		op.Opacity, _ = strconv.Atoi(m[1])
+	}
+	return op, nil
+}
@@ -0,0 +1,95 @@
+package cdn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("https://ucarecdn.com/" + testUUID + "/-/resize/800x/-/scale_crop/200x100/smart/-/enhance/50/my%20photo.jpg?token=abc")
+	require.NoError(t, err)
+	assert.Equal(t, &URL{
+		Base: "https://ucarecdn.com",
+		UUID: testUUID,
+		Ops: []Op{
+			ResizeOp{Width: 800},
+			ScaleCropOp{Width: 200, Height: 100, Alignment: AlignSmart},
+			RawOp{OpName: "enhance", OpArgs: []string{"50"}},
+		},
+		Filename: "my photo.jpg",
+		RawQuery: "token=abc",
+	}, u)
+
+	u, err = Parse(testUUID + "~3/nth/2/-/preview/")
+	require.NoError(t, err)
+	assert.Equal(t, &URL{GroupID: testUUID + "~3", Nth: 2, Ops: []Op{PreviewOp{}}}, u)
+
+	u, err = Parse("https://cdn.example.com/media/" + testUUID + "/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/media", u.Base)
+	assert.Empty(t, u.Ops)
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	builders := []*Builder{
+		New(testUUID),
+		New(testUUID).Filename("photo.jpg"),
+		New(testUUID).Resize(0, 600).Crop(10, 20, "5,10").Preview(100, 100).Format(FormatPNG),
+		New(testUUID).Quality(QualityLightest).Progressive(false).Rotate(90).Flip().Mirror().Grayscale(),
+		New(testUUID).Blur(0).Blur(300).Sharpen(0).Sharpen(3).SetFill("fff").Stretch(StretchOff),
+		New(testUUID).Overlay(OverlayOp{UUID: overlayUUID, Width: 30, Height: 30, Position: "10p,90p", Opacity: 5}),
+		New(testUUID).StripMeta(StripMetaAll).Op(RawOp{OpName: "autorotate", OpArgs: []string{"no"}}),
+		NewGroupFile(testUUID+"~2", 1).ScaleCrop(50, 50, AlignSmartFacesObjects).Filename("a b.png"),
+	}
+
+	for _, b := range builders {
+		want, err := b.URLWithBase("https://ucarecdn.com")
+		require.NoError(t, err)
+
+		u, err := Parse(want)
+		require.NoError(t, err, want)
+		assert.Equal(t, want, u.String())
+		assert.Equal(t, b.Ops(), u.Builder().Ops())
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"https://ucarecdn.com/", `cdn: malformed CDN URL "https://ucarecdn.com/": no file UUID or group ID`},
+		{"https://ucarecdn.com/" + testUUID, `cdn: malformed CDN URL "https://ucarecdn.com/` + testUUID + `": missing slash after "` + testUUID + `"`},
+		{testUUID + "/resize/800x/", `cdn: malformed CDN URL "` + testUUID + `/resize/800x/": expected "-" before operation, got "resize"`},
+		{testUUID + "/-/-/", `cdn: malformed CDN URL "` + testUUID + `/-/-/": missing operation name after "-"`},
+		{testUUID + "~2/-/resize/x/", `cdn: malformed CDN URL "` + testUUID + `~2/-/resize/x/": group URL must point at a file with /nth/<index>/`},
+		{testUUID + "~2/nth/x/", `cdn: malformed CDN URL "` + testUUID + `~2/nth/x/": invalid group file index "x"`},
+		{testUUID + "~2/nth/2/", "cdn: group file index out of range: 2 is out of 0..1"},
+		{"media/" + testUUID + "/", `cdn: malformed CDN URL "media/` + testUUID + `/": unexpected path prefix "media"`},
+		{"javascript://x/" + testUUID + "/%0aalert(1)", `cdn: malformed CDN URL "javascript://x/` + testUUID + `/%0aalert(1)": unsupported scheme "javascript"`},
+		{"data:text/html," + testUUID + "/", `cdn: malformed CDN URL "data:text/html,` + testUUID + `/": opaque URLs are not supported`},
+		{"ftp://ucarecdn.com/" + testUUID + "/", `cdn: malformed CDN URL "ftp://ucarecdn.com/` + testUUID + `/": unsupported scheme "ftp"`},
+		{"//ucarecdn.com/" + testUUID + "/", `cdn: malformed CDN URL "//ucarecdn.com/` + testUUID + `/": missing scheme`},
+		{"https:/" + testUUID + "/", `cdn: malformed CDN URL "https:/` + testUUID + `/": missing host`},
+		{testUUID + "/-/resize/800x", "cdn: invalid operation resize: expected 1 argument, got 0"},
+		{testUUID + "/-/resize/big/", `cdn: invalid operation resize: invalid dimensions "big"`},
+		{testUUID + "/-/crop/1x1/a/b/", "cdn: invalid operation crop: expected 1 to 2 arguments, got 3"},
+		{testUUID + "/-/rotate/45/", "cdn: invalid operation rotate: angle 45 must be one of 0, 90, 180 or 270"},
+		{testUUID + "/-/progressive/maybe/", `cdn: invalid operation progressive: expected "yes" or "no", got "maybe"`},
+		{testUUID + "/-/flip/x/", "cdn: invalid operation flip: expected 0 arguments, got 1"},
+		{testUUID + "/-/overlay/" + overlayUUID + "/50x50/", `cdn: invalid operation overlay: invalid size "50x50"`},
+	}
+
+	for _, tc := range tests {
+		_, err := Parse(tc.url)
+		assert.EqualError(t, err, tc.wantErr, tc.url)
+	}
+}
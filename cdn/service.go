@@ -0,0 +1,141 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// MaxMainColors is the maximum number of main colors MainColors returns
+const MaxMainColors = 20
+
+// Service describes the CDN image analysis API
+type Service interface {
+	ImageInfo(ctx context.Context, uuid string, ops ...Op) (file.ImageInfo, error)
+	MainColors(ctx context.Context, uuid string, n int) ([]Color, error)
+	DetectFaces(ctx context.Context, uuid string) (Faces, error)
+}
+
+type service struct {
+	client  ucare.Client
+	cdnBase string
+}
+
+// NewService creates new CDN service. Requests are made through the client
+// HTTP client and retried when throttled, see ucare.ClientDownload.
+func NewService(client ucare.Client) Service {
+	return service{
+		client:  client,
+		cdnBase: ucare.ClientCDNBase(client),
+	}
+}
+
+// Color is an RGB color
+type Color [3]uint8
+
+// Hex returns the color as 6 hex digits, e.g. "ff8000"
+func (c Color) Hex() string { return fmt.Sprintf("%02x%02x%02x", c[0], c[1], c[2]) }
+
+// Face is a rectangle of a face detected in the image, in pixels
+type Face struct {
+	X, Y, Width, Height int
+}
+
+// UnmarshalJSON implements json.Unmarshaler, faces are returned as
+// [x, y, width, height] arrays
+func (f *Face) UnmarshalJSON(b []byte) error {
+	var v [4]int
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*f = Face{X: v[0], Y: v[1], Width: v[2], Height: v[3]}
+	return nil
+}
+
+// Faces holds the faces detected in the image
+type Faces struct {
+	// Width and Height are the dimensions of the image the face
+	// coordinates are relative to
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	Faces []Face `json:"faces"`
+}
+
+// ImageInfo returns info of the image with the operations applied, e.g.
+// its dimensions after resizing
+func (s service) ImageInfo(
+	ctx context.Context,
+	uuid string,
+	ops ...Op,
+) (data file.ImageInfo, err error) {
+	b := New(uuid)
+	for _, op := range ops {
+		b = b.Op(op)
+	}
+	err = s.getJSON(ctx, b.Op(RawOp{OpName: "json"}), &data)
+	return
+}
+
+// MainColors returns up to n main colors of the image, n is within
+// 1..MaxMainColors
+func (s service) MainColors(
+	ctx context.Context,
+	uuid string,
+	n int,
+) ([]Color, error) {
+	if n < 1 || n > MaxMainColors {
+		return nil, opErr("main_colors", "number of colors %d must be within 1..%d", n, MaxMainColors)
+	}
+	var data struct {
+		MainColors []Color `json:"main_colors"`
+	}
+	op := RawOp{OpName: "main_colors", OpArgs: []string{strconv.Itoa(n)}}
+	err := s.getJSON(ctx, New(uuid).Op(op), &data)
+	return data.MainColors, err
+}
+
+// DetectFaces returns the faces detected in the image
+func (s service) DetectFaces(ctx context.Context, uuid string) (data Faces, err error) {
+	err = s.getJSON(ctx, New(uuid).Op(RawOp{OpName: "detect_faces"}), &data)
+	return
+}
+
+func (s service) getJSON(ctx context.Context, b *Builder, resdata any) error {
+	if s.cdnBase == "" {
+		return ErrNoCDNBase
+	}
+	rawURL, err := b.URLWithBase(s.cdnBase)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("requesting %s", rawURL)
+
+	resp, err := ucare.ClientDownload(s.client, req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		detail := strings.TrimSpace(string(body))
+		if detail == "" {
+			detail = resp.Status
+		}
+		return ucare.APIError{StatusCode: resp.StatusCode, Detail: detail}
+	}
+	return json.NewDecoder(resp.Body).Decode(resdata)
+}
@@ -0,0 +1,69 @@
+package cdn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+func newTestService(srv *httptest.Server) Service {
+	client := uctest.NewServerClient(srv)
+	client.CDN = srv.URL
+	return NewService(client)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/" + testUUID + "/-/resize/100x/-/json/":
+			_, _ = w.Write([]byte(`{"id": "` + testUUID + `", "width": 100, "height": 75, "format": "JPEG", "orientation": 6, "dpi": [72, 72], "sequence": false}`))
+		case "/" + testUUID + "/-/main_colors/2/":
+			_, _ = w.Write([]byte(`{"main_colors": [[255, 128, 0], [1, 2, 3]]}`))
+		case "/" + testUUID + "/-/detect_faces/":
+			_, _ = w.Write([]byte(`{"id": "` + testUUID + `", "width": 640, "height": 480, "faces": [[10, 20, 100, 120]]}`))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := newTestService(srv)
+		ctx := context.Background()
+
+		info, err := svc.ImageInfo(ctx, testUUID, ResizeOp{Width: 100})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), info.Width)
+		assert.Equal(t, uint64(75), info.Height)
+		assert.Equal(t, "JPEG", info.Format)
+		assert.Equal(t, []float64{72, 72}, info.DPI)
+
+		colors, err := svc.MainColors(ctx, testUUID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []Color{{255, 128, 0}, {1, 2, 3}}, colors)
+		assert.Equal(t, "ff8000", colors[0].Hex())
+
+		faces, err := svc.DetectFaces(ctx, testUUID)
+		require.NoError(t, err)
+		assert.Equal(t, Faces{Width: 640, Height: 480, Faces: []Face{{X: 10, Y: 20, Width: 100, Height: 120}}}, faces)
+
+		_, err = svc.ImageInfo(ctx, testUUID)
+		var apiErr ucare.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+		_, err = svc.MainColors(ctx, testUUID, 21)
+		assert.ErrorIs(t, err, ErrInvalidOp)
+		_, err = svc.ImageInfo(ctx, testUUID, RotateOp{Angle: 1})
+		assert.ErrorIs(t, err, ErrInvalidOp)
+	})
+
+	_, err := NewService(&uctest.Client{}).DetectFaces(context.Background(), testUUID)
+	assert.ErrorIs(t, err, ErrNoCDNBase)
+}
@@ -0,0 +1,249 @@
+package cdn
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// TokenParam is the name of the secure delivery token query parameter and
+// path segment prefix
+const TokenParam = "token"
+
+// DefaultTokenTTL is used when SignerParams.TTL is not set
+const DefaultTokenTTL = time.Hour
+
+// TokenForm tells where the secure delivery token is put in a URL
+type TokenForm int
+
+// Secure delivery token forms
+const (
+	// TokenQuery puts the token into the query, e.g.
+	// https://cdn.example.com/uuid/?token=exp=...~acl=...~hmac=...
+	TokenQuery TokenForm = iota
+	// TokenPath embeds the token as the first path segment, e.g.
+	// https://cdn.example.com/token=exp=...~acl=...~hmac=.../uuid/
+	TokenPath
+)
+
+// Secure delivery token errors
+var (
+	ErrInvalidKey   = errors.New("cdn: secure delivery key must be hex-encoded")
+	ErrNoToken      = errors.New("cdn: URL has no secure delivery token")
+	ErrInvalidToken = errors.New("cdn: invalid secure delivery token")
+	ErrTokenExpired = errors.New("cdn: secure delivery token expired")
+	ErrACLMismatch  = errors.New("cdn: secure delivery token ACL does not cover the URL")
+)
+
+// SignerParams holds Signer params
+type SignerParams struct {
+	// TTL is how long signed URLs are valid for.
+	// Defaults to DefaultTokenTTL.
+	TTL time.Duration
+
+	// Form tells where the token is put, TokenQuery by default
+	Form TokenForm
+}
+
+// Signer signs CDN URLs with time-limited tokens of the token-based secure
+// delivery, "exp=<unix time>~acl=<path pattern>~hmac=<HMAC-SHA256>". The
+// token grants access to the URL paths matching the ACL pattern, where "*"
+// matches any sequence of characters.
+//
+// Signer verifies tokens as well, e.g. for proxies emulating the CDN in
+// tests.
+type Signer struct {
+	key    []byte
+	params SignerParams
+	now    func() time.Time
+}
+
+// NewSigner returns a signer with the hex-encoded secret key of the
+// project secure delivery settings
+func NewSigner(key string, params SignerParams) (*Signer, error) {
+	k, err := hex.DecodeString(key)
+	if err != nil || len(k) == 0 {
+		return nil, ErrInvalidKey
+	}
+	if params.TTL <= 0 {
+		params.TTL = DefaultTokenTTL
+	}
+	return &Signer{key: k, params: params, now: time.Now}, nil
+}
+
+// Token returns a token granting access to the paths matching the ACL
+// until the expiry time
+func (s *Signer) Token(acl string, expires time.Time) string {
+	fields := "exp=" + strconv.FormatInt(expires.Unix(), 10) + "~acl=" + acl
+	return fields + "~hmac=" + s.mac(fields)
+}
+
+func (s *Signer) mac(fields string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(fields))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Sign returns the URL signed with a token granting access to the URL
+// path and any path it prefixes, e.g. the one with a file name appended
+func (s *Signer) Sign(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return s.SignACL(rawURL, u.EscapedPath()+"*")
+}
+
+// SignACL returns the URL signed with a token granting access to the
+// paths matching the ACL, e.g. "/uuid/*" for all of the file variants
+func (s *Signer) SignACL(rawURL, acl string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("cdn: signing relative URL %q", rawURL)
+	}
+	expires := s.now().Add(s.params.TTL)
+
+	if s.params.Form == TokenPath {
+		// the ACL slashes would split the token segment, so it is
+		// signed escaped
+		token := s.Token(url.PathEscape(acl), expires)
+		u.RawPath = "/" + TokenParam + "=" + token + u.EscapedPath()
+		u.Path, err = url.PathUnescape(u.RawPath)
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	}
+
+	// the token is put as is, escaping would break its verification
+	q := TokenParam + "=" + s.Token(acl, expires)
+	if u.RawQuery != "" {
+		q = u.RawQuery + "&" + q
+	}
+	u.RawQuery = q
+	return u.String(), nil
+}
+
+// Verify checks the URL token is valid, not expired and grants access to
+// the URL path
+func (s *Signer) Verify(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	escPath := u.EscapedPath()
+	var token string
+	prefix := "/" + TokenParam + "="
+	inPath := strings.HasPrefix(escPath, prefix)
+	if inPath {
+		token, escPath, _ = strings.Cut(escPath[len(prefix):], "/")
+		escPath = "/" + escPath
+	} else {
+		for _, kv := range strings.Split(u.RawQuery, "&") {
+			if v, ok := strings.CutPrefix(kv, TokenParam+"="); ok {
+				token = v
+			}
+		}
+	}
+	if token == "" {
+		return ErrNoToken
+	}
+
+	fields, mac, ok := strings.Cut(token, "~hmac=")
+	if !ok || !hmac.Equal([]byte(mac), []byte(s.mac(fields))) {
+		return ErrInvalidToken
+	}
+
+	var exp int64 = -1
+	var acl string
+	for _, field := range strings.Split(fields, "~") {
+		k, v, _ := strings.Cut(field, "=")
+		switch k {
+		case "exp":
+			if exp, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return ErrInvalidToken
+			}
+		case "acl":
+			acl = v
+		}
+	}
+	if inPath {
+		acl, err = url.PathUnescape(acl)
+	}
+	if exp < 0 || acl == "" || err != nil {
+		return ErrInvalidToken
+	}
+	if !s.now().Before(time.Unix(exp, 0)) {
+		return ErrTokenExpired
+	}
+
+	// multiple ACL patterns are separated with "!"
+	for _, pattern := range strings.Split(acl, "!") {
+		if matchACL(pattern, escPath) {
+			return nil
+		}
+	}
+	return ErrACLMismatch
+}
+
+// matchACL matches the path against the pattern, where "*" matches any
+// sequence of characters including slashes
+func matchACL(pattern, path string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	path = path[len(parts[0]):]
+	if len(parts) == 1 {
+		return path == ""
+	}
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(path, part)
+		if i < 0 {
+			return false
+		}
+		path = path[i+len(part):]
+	}
+	return strings.HasSuffix(path, parts[len(parts)-1])
+}
+
+// SignedURL returns the absolute URL against the client CDN base signed
+// for the file, so the token grants access to all of its variants
+func (b *Builder) SignedURL(client ucare.Client, s *Signer) (string, error) {
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		return "", ErrNoCDNBase
+	}
+	return b.signedURL(base, s)
+}
+
+func (b *Builder) signedURL(base string, s *Signer) (string, error) {
+	rawURL, err := b.URLWithBase(base)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	p, _ := b.Path()
+	source := b.uuid
+	if b.groupID != "" {
+		source = b.groupID + "/nth/" + strconv.Itoa(b.nth)
+	}
+	// the CDN base path prefix, if any, is a part of the ACL
+	prefix := strings.TrimSuffix(u.EscapedPath(), p)
+	return s.SignACL(rawURL, prefix+source+"/*")
+}
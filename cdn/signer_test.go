@@ -0,0 +1,113 @@
+package cdn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+const testKey = "00112233445566778899aabbccddeeff"
+
+var signedAt = time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)
+
+func testSigner(t *testing.T, form TokenForm) *Signer {
+	s, err := NewSigner(testKey, SignerParams{Form: form})
+	require.NoError(t, err)
+	s.now = func() time.Time { return signedAt }
+	return s
+}
+
+func TestSigner_Token(t *testing.T) {
+	t.Parallel()
+
+	s := testSigner(t, TokenQuery)
+	assert.Equal(t,
+		"exp=1717243200~acl=/"+testUUID+"/*~hmac=b6c39715ccb6ed9cd75f413a9053a4a1b7819ae618bf6605765210481e3ae180",
+		s.Token("/"+testUUID+"/*", signedAt.Add(time.Hour)),
+	)
+
+	_, err := NewSigner("not hex", SignerParams{})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestBuilder_SignedURL(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://cdn.example.com/media"}
+	b := New(testUUID).Resize(100, 0).Filename("photo.jpg")
+
+	for _, tc := range []struct {
+		form TokenForm
+		want string
+	}{
+		{TokenQuery, "https://cdn.example.com/media/" + testUUID + "/-/resize/100x/photo.jpg?token=exp=1717243200~acl=/media/" + testUUID + "/*~hmac="},
+		{TokenPath, "https://cdn.example.com/token=exp=1717243200~acl=%2Fmedia%2F" + testUUID + "%2F%2A~hmac="},
+	} {
+		s := testSigner(t, tc.form)
+		signed, err := b.SignedURL(client, s)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(signed, tc.want), signed)
+		require.NoError(t, s.Verify(signed))
+
+		// other variants of the file are covered by the token as well
+		other := strings.Replace(signed, "resize/100x", "resize/2000x", 1)
+		assert.NoError(t, s.Verify(other))
+	}
+}
+
+func TestSigner_Verify(t *testing.T) {
+	t.Parallel()
+
+	s := testSigner(t, TokenQuery)
+	fileURL := "https://cdn.example.com/" + testUUID + "/-/preview/"
+	signed, err := s.Sign(fileURL)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify(signed))
+	require.NoError(t, s.Verify(signed+"&other=1"))
+
+	other, err := NewSigner("ff", SignerParams{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		url     string
+		now     time.Time
+		wantErr error
+	}{
+		{"no_token", s, fileURL, signedAt, ErrNoToken},
+		{"other_key", other, signed, signedAt, ErrInvalidToken},
+		{"tampered", s, strings.Replace(signed, "exp=1717243200", "exp=1717246800", 1), signedAt, ErrInvalidToken},
+		{"expired", s, signed, signedAt.Add(time.Hour), ErrTokenExpired},
+		{"other_path", s, strings.Replace(signed, "/-/preview/", "/-/resize/10x/", 1), signedAt, ErrACLMismatch},
+	}
+	for _, tc := range tests {
+		signer := *tc.signer
+		signer.now = func() time.Time { return tc.now }
+		assert.ErrorIs(t, signer.Verify(tc.url), tc.wantErr, tc.name)
+	}
+}
+
+func TestMatchACL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/a/*", "/a/", true},
+		{"/a/*", "/a/b/c.jpg", true},
+		{"/a/*", "/b/", false},
+		{"/a/", "/a/", true},
+		{"/a/", "/a/b", false},
+		{"/*/x/*.jpg", "/a/b/x/c.jpg", true},
+		{"/*/x/*.jpg", "/a/b/x/c.png", false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, matchACL(tc.pattern, tc.path), tc.pattern+" "+tc.path)
+	}
+}
@@ -0,0 +1,160 @@
+package cdn
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// ErrNoWidths is returned when no widths are given to build srcset from
+var ErrNoWidths = errors.New("cdn: srcset requires at least one width")
+
+// SrcsetParams holds params for the Srcset and Picture methods
+type SrcsetParams struct {
+	// Widths are the image widths in pixels
+	Widths []int
+
+	// Formats are the formats of the Picture sources in the order of
+	// preference, e.g. FormatWebP followed by FormatJPEG. Srcset ignores
+	// them.
+	//
+	// AVIF is not a CDN output format, it is only served through
+	// FormatAuto to the browsers accepting it. Put FormatAuto first to
+	// let the CDN pick AVIF or WebP, see the Picture example.
+	Formats []ImageFormat
+
+	// Image is the original image info, e.g. file.ContentInfo.Image.
+	// When set, widths exceeding the image display width are replaced
+	// with it, so the image is never upscaled.
+	Image *file.ImageInfo
+}
+
+// Source describes a <picture> source element
+type Source struct {
+	// Format is the format of the source images
+	Format ImageFormat
+
+	// Type is the MIME type of the source images, e.g. "image/webp".
+	// It is empty for FormatAuto and FormatPreserve.
+	Type string
+
+	// Srcset lists the source images of the widths
+	Srcset string
+}
+
+// Srcset returns the srcset attribute value listing the image resized to
+// the widths, e.g. "https://.../-/resize/320x/ 320w, https://.../-/resize/640x/ 640w".
+// The resize operation follows the builder operations.
+func (b *Builder) Srcset(client ucare.Client, params SrcsetParams) (string, error) {
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		return "", ErrNoCDNBase
+	}
+	return b.srcset(base, params.widths(), "")
+}
+
+// Picture returns the <picture> sources, one per each of the formats.
+// The format replaces the builder one, if any.
+//
+// Example usage:
+//
+//	// AVIF or WebP negotiated by the CDN, JPEG for the rest
+//	sources, err := cdn.New(fileID).Picture(client, cdn.SrcsetParams{
+//		Widths:  []int{320, 640, 1280},
+//		Formats: []cdn.ImageFormat{cdn.FormatAuto, cdn.FormatJPEG},
+//	})
+//	if err != nil {
+//		// handle error
+//	}
+//	// <picture>
+//	//   <source srcset="{{ (index sources 0).Srcset }}">
+//	//   <img srcset="{{ (index sources 1).Srcset }}" ...>
+//	// </picture>
+func (b *Builder) Picture(client ucare.Client, params SrcsetParams) ([]Source, error) {
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		return nil, ErrNoCDNBase
+	}
+	widths := params.widths()
+	sources := make([]Source, 0, len(params.Formats))
+	for _, format := range params.Formats {
+		srcset, err := b.srcset(base, widths, format)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, Source{
+			Format: format,
+			Type:   mimeTypes[format],
+			Srcset: srcset,
+		})
+	}
+	return sources, nil
+}
+
+var mimeTypes = map[ImageFormat]string{
+	FormatJPEG: "image/jpeg",
+	FormatPNG:  "image/png",
+	FormatWebP: "image/webp",
+}
+
+func (b *Builder) srcset(base string, widths []int, format ImageFormat) (string, error) {
+	if len(widths) == 0 {
+		return "", ErrNoWidths
+	}
+	candidates := make([]string, len(widths))
+	for i, w := range widths {
+		u, err := b.variant(w, format).URLWithBase(base)
+		if err != nil {
+			return "", err
+		}
+		candidates[i] = u + " " + strconv.Itoa(w) + "w"
+	}
+	return strings.Join(candidates, ", "), nil
+}
+
+// variant resizes the image to the width after the rest of the operations
+// and converts it to the format, if set, instead of the builder one.
+// Format operations are kept last.
+func (b *Builder) variant(width int, format ImageFormat) *Builder {
+	nb := *b
+	nb.ops = nil
+	var formats []Op
+	for _, op := range b.ops {
+		if _, ok := op.(FormatOp); ok {
+			if format == "" {
+				formats = append(formats, op)
+			}
+			continue
+		}
+		nb.ops = append(nb.ops, op)
+	}
+	res := nb.Resize(width, 0)
+	if format != "" {
+		res = res.Format(format)
+	}
+	for _, op := range formats {
+		res = res.Op(op)
+	}
+	return res
+}
+
+// widths returns the sorted unique widths clamped to the image width
+func (p SrcsetParams) widths() []int {
+	limit := 0
+	if p.Image != nil {
+		limit = int(p.Image.DisplayWidth())
+	}
+	widths := make([]int, 0, len(p.Widths))
+	for _, w := range p.Widths {
+		if limit > 0 && w > limit {
+			w = limit
+		}
+		widths = append(widths, w)
+	}
+	slices.Sort(widths)
+	return slices.Compact(widths)
+}
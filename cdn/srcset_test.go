@@ -0,0 +1,62 @@
+package cdn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+func TestBuilder_Srcset(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://cdn.example.com"}
+	base := "https://cdn.example.com/" + testUUID
+
+	srcset, err := New(testUUID).Format(FormatAuto).Sharpen(0).Srcset(client, SrcsetParams{
+		Widths: []int{640, 320},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		base+"/-/sharp/-/resize/320x/-/format/auto/ 320w, "+
+			base+"/-/sharp/-/resize/640x/-/format/auto/ 640w",
+		srcset,
+	)
+
+	_, err = New(testUUID).Srcset(client, SrcsetParams{})
+	assert.ErrorIs(t, err, ErrNoWidths)
+	_, err = New(testUUID).Srcset(client, SrcsetParams{Widths: []int{6000}})
+	assert.ErrorIs(t, err, ErrInvalidOp)
+}
+
+func TestBuilder_Picture(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: "https://cdn.example.com"}
+	base := "https://cdn.example.com/" + testUUID
+
+	// the image is rotated, so its display width is its height
+	image := &file.ImageInfo{Width: 1200, Height: 800, Orientation: 6}
+	sources, err := New(testUUID).Format(FormatPNG).Filename("photo").Picture(client, SrcsetParams{
+		Widths:  []int{400, 800, 1600, 2400},
+		Formats: []ImageFormat{FormatWebP, FormatAuto},
+		Image:   image,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Source{{
+		Format: FormatWebP,
+		Type:   "image/webp",
+		Srcset: base + "/-/resize/400x/-/format/webp/photo 400w, " +
+			base + "/-/resize/800x/-/format/webp/photo 800w",
+	}, {
+		Format: FormatAuto,
+		Srcset: base + "/-/resize/400x/-/format/auto/photo 400w, " +
+			base + "/-/resize/800x/-/format/auto/photo 800w",
+	}}, sources)
+
+	// AVIF is only served through FormatAuto
+	_, err = New(testUUID).Picture(client, SrcsetParams{Widths: []int{100}, Formats: []ImageFormat{"avif"}})
+	assert.ErrorIs(t, err, ErrInvalidOp)
+}
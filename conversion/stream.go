@@ -0,0 +1,213 @@
+package conversion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/cdn"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// BuildAdaptiveVideoPath returns the CDN path of the HLS playlist serving
+// the stored video with adaptive bitrate streaming
+func BuildAdaptiveVideoPath(uuid string) (string, error) {
+	if uuid == "" {
+		return "", errEmptyUUID
+	}
+	return uuid + "/adaptive_video/", nil
+}
+
+// AdaptiveVideoURL returns the URL of the HLS playlist of the stored video
+// built against the client CDN base, see ucare.ClientCDNBase.
+//
+// Example usage:
+//
+//	playlist, err := conversion.AdaptiveVideoURL(client, videoID)
+//	if err != nil {
+//		// handle error
+//	}
+//	// <video src="{{ playlist }}"> with an HLS capable player
+func AdaptiveVideoURL(client ucare.Client, uuid string) (string, error) {
+	if err := cdn.New(uuid).Err(); err != nil {
+		return "", err
+	}
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		return "", cdn.ErrNoCDNBase
+	}
+	path, err := BuildAdaptiveVideoPath(uuid)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(base, "/") + "/" + path, nil
+}
+
+// ThumbnailOptions holds video thumbnail URL options
+type ThumbnailOptions struct {
+	// GroupID is the group of the video thumbnails, see
+	// Job.ThumbnailsGroupID. Thumbnails are generated when the video is
+	// converted with VideoPathOptions.Thumbs set.
+	GroupID string
+	// Index is the zero-based thumbnail index in the group
+	Index int
+	// Width and Height downscale the thumbnail to fit the size when both
+	// are set. Either of them alone resizes the thumbnail to the width or
+	// the height keeping its aspect ratio.
+	Width  int
+	Height int
+	// Format converts the thumbnail to the image format when set
+	Format cdn.ImageFormat
+	// Ops are applied after the rest of the options
+	Ops []cdn.Op
+}
+
+// ThumbnailURL returns the CDN URL of the video thumbnail built against the
+// client CDN base
+func ThumbnailURL(client ucare.Client, opts ThumbnailOptions) (string, error) {
+	b := cdn.NewGroupFile(opts.GroupID, opts.Index)
+	switch {
+	case opts.Width > 0 && opts.Height > 0:
+		b = b.Preview(opts.Width, opts.Height)
+	case opts.Width > 0 || opts.Height > 0:
+		b = b.Resize(opts.Width, opts.Height)
+	}
+	if opts.Format != "" {
+		b = b.Format(opts.Format)
+	}
+	for _, op := range opts.Ops {
+		b = b.Op(op)
+	}
+	return b.URL(client)
+}
+
+// Rendition is a single rung of the adaptive bitrate ladder
+type Rendition struct {
+	// Name identifies the rendition in the manifest, e.g. "720p"
+	Name string
+	// Size is the output dimensions, see VideoPathOptions.Size
+	Size    string
+	Quality Quality
+}
+
+// DefaultLadder is a common adaptive bitrate ladder. The renditions are
+// scaled by height keeping the source aspect ratio.
+var DefaultLadder = []Rendition{
+	{Name: "1080p", Size: "x1080", Quality: QualityBetter},
+	{Name: "720p", Size: "x720", Quality: QualityNormal},
+	{Name: "480p", Size: "x480", Quality: QualityLighter},
+	{Name: "360p", Size: "x360", Quality: QualityLightest},
+}
+
+// LadderParams holds params for the ConvertLadder function
+type LadderParams struct {
+	// Renditions to convert the video to. Defaults to DefaultLadder.
+	Renditions []Rendition
+	// Format is the target video format. Defaults to "mp4".
+	Format string
+	// ToStore is passed as Params.ToStore to every conversion job
+	ToStore *string
+	// Thumbs is the number of thumbnails generated along with the first
+	// rendition
+	Thumbs int
+}
+
+// RenditionJob holds the conversion job started for a rendition
+type RenditionJob struct {
+	Rendition
+	// Path is the conversion path of the rendition
+	Path string
+	// ID is the UUID of the converted video
+	ID string
+	// Token is the job token to request the status with VideoStatus
+	Token int64
+}
+
+// LadderManifest holds the conversion jobs of the ladder renditions
+type LadderManifest struct {
+	// Source is the UUID of the source video
+	Source string
+	// Renditions holds the started jobs in the ladder order
+	Renditions []RenditionJob
+	// ThumbnailsGroupID is the group of the video thumbnails, if requested
+	ThumbnailsGroupID *string
+}
+
+// ConvertLadder starts a video conversion job for every rendition of the
+// ladder and returns the manifest of the resulting UUIDs. The jobs are
+// started one by one and a failure to start one does not stop the rest:
+// the manifest holds the started jobs and the failures are joined into
+// the returned error. An invalid UUID fails the ladder before any job is
+// started. Use VideoStatus to wait for the jobs to finish.
+//
+// Example usage:
+//
+//	manifest, err := conversion.ConvertLadder(ctx, convSvc, videoID, conversion.LadderParams{
+//		ToStore: ucare.String(conversion.ToStoreTrue),
+//	})
+func ConvertLadder(
+	ctx context.Context,
+	svc Service,
+	uuid string,
+	params LadderParams,
+) (LadderManifest, error) {
+	if err := cdn.New(uuid).Err(); err != nil {
+		return LadderManifest{Source: uuid}, err
+	}
+
+	renditions := params.Renditions
+	if len(renditions) == 0 {
+		renditions = DefaultLadder
+	}
+
+	manifest := LadderManifest{Source: uuid}
+	var errs []error
+	for i, r := range renditions {
+		opts := VideoPathOptions{
+			UUID:       uuid,
+			Format:     params.Format,
+			Size:       r.Size,
+			ResizeMode: ResizeModePreserveRatio,
+			Quality:    r.Quality,
+		}
+		if r.Size == "" {
+			opts.ResizeMode = ""
+		}
+		if i == 0 {
+			opts.Thumbs = params.Thumbs
+		}
+		path, err := BuildVideoPath(opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("conversion: rendition %s: %w", r.Name, err))
+			continue
+		}
+
+		log.Debugf("converting rendition %s: %s", r.Name, path)
+
+		res, err := svc.Video(ctx, Params{Paths: []string{path}, ToStore: params.ToStore})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("conversion: rendition %s: %w", r.Name, err))
+			continue
+		}
+		if problem, ok := res.Problems[path]; ok || len(res.Jobs) == 0 {
+			if problem == "" {
+				problem = "no conversion job started"
+			}
+			errs = append(errs, fmt.Errorf("conversion: rendition %s: %s", r.Name, problem))
+			continue
+		}
+
+		job := res.Jobs[0]
+		manifest.Renditions = append(manifest.Renditions, RenditionJob{
+			Rendition: r,
+			Path:      path,
+			ID:        job.ID,
+			Token:     job.Token,
+		})
+		if job.ThumbnailsGroupID != nil {
+			manifest.ThumbnailsGroupID = job.ThumbnailsGroupID
+		}
+	}
+	return manifest, errors.Join(errs...)
+}
@@ -0,0 +1,186 @@
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/cdn"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+const (
+	testVideoID   = "a5ed4c7a-d1b6-4bb8-9e0e-6a1a6e6b2b5c"
+	testThumbsID  = "b7f1d0a2-3c4e-4f5a-8b6c-7d8e9f0a1b2c~5"
+	testStreamCDN = "https://cdn.example.com"
+)
+
+func TestAdaptiveVideoURL(t *testing.T) {
+	t.Parallel()
+
+	got, err := AdaptiveVideoURL(&uctest.Client{CDN: testStreamCDN + "/"}, testVideoID)
+	require.NoError(t, err)
+	assert.Equal(t, testStreamCDN+"/"+testVideoID+"/adaptive_video/", got)
+
+	_, err = AdaptiveVideoURL(&uctest.Client{CDN: testStreamCDN}, "not-a-uuid")
+	assert.ErrorIs(t, err, cdn.ErrInvalidUUID)
+
+	_, err = AdaptiveVideoURL(&uctest.Client{}, testVideoID)
+	assert.ErrorIs(t, err, cdn.ErrNoCDNBase)
+
+	_, err = BuildAdaptiveVideoPath("")
+	assert.ErrorIs(t, err, errEmptyUUID)
+}
+
+func TestThumbnailURL(t *testing.T) {
+	t.Parallel()
+
+	client := &uctest.Client{CDN: testStreamCDN}
+
+	tests := []struct {
+		name string
+		opts ThumbnailOptions
+		want string
+		err  error
+	}{
+		{
+			name: "plain",
+			opts: ThumbnailOptions{GroupID: testThumbsID},
+			want: testStreamCDN + "/" + testThumbsID + "/nth/0/",
+		},
+		{
+			name: "typed_options",
+			opts: ThumbnailOptions{
+				GroupID: testThumbsID,
+				Index:   4,
+				Width:   320,
+				Height:  180,
+				Format:  cdn.FormatWebP,
+				Ops:     []cdn.Op{cdn.QualityOp{Quality: cdn.QualityLighter}},
+			},
+			want: testStreamCDN + "/" + testThumbsID + "/nth/4/-/preview/320x180/-/format/webp/-/quality/lighter/",
+		},
+		{
+			name: "width_only",
+			opts: ThumbnailOptions{GroupID: testThumbsID, Width: 320},
+			want: testStreamCDN + "/" + testThumbsID + "/nth/0/-/resize/320x/",
+		},
+		{
+			name: "height_only",
+			opts: ThumbnailOptions{GroupID: testThumbsID, Height: 180},
+			want: testStreamCDN + "/" + testThumbsID + "/nth/0/-/resize/x180/",
+		},
+		{
+			name: "index_out_of_range",
+			opts: ThumbnailOptions{GroupID: testThumbsID, Index: 5},
+			err:  cdn.ErrInvalidNth,
+		},
+		{
+			name: "invalid_group",
+			opts: ThumbnailOptions{GroupID: testVideoID},
+			err:  cdn.ErrInvalidGroupID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ThumbnailURL(client, tt.opts)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertLadder(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, convertVideoFormat, r.URL.Path)
+
+		var params struct {
+			Paths []string `json:"paths"`
+			Store string   `json:"store"`
+		}
+		require.NoError(t, json.Unmarshal(uctest.ReadBody(t, r), &params))
+		require.Len(t, params.Paths, 1)
+		assert.Equal(t, ToStoreTrue, params.Store)
+		path := params.Paths[0]
+		paths = append(paths, path)
+
+		if strings.Contains(path, "x480") {
+			uctest.RespondJSON(t, w, map[string]any{
+				"problems": map[string]string{path: "bad size"},
+				"result":   []any{},
+			})
+			return
+		}
+		job := map[string]any{
+			"original_source": path,
+			"uuid":            fmt.Sprintf("out-%d", len(paths)),
+			"token":           len(paths),
+		}
+		if strings.Contains(path, "thumbs~") {
+			job["thumbnails_group_id"] = testThumbsID
+		}
+		uctest.RespondJSON(t, w, map[string]any{"problems": map[string]string{}, "result": []any{job}})
+	}), func(t *testing.T, srv *httptest.Server) {
+		manifest, err := ConvertLadder(context.Background(), NewService(uctest.NewServerClient(srv)), testVideoID, LadderParams{
+			ToStore: ucare.String(ToStoreTrue),
+			Thumbs:  5,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rendition 480p: bad size")
+
+		assert.Equal(t, []string{
+			testVideoID + "/video/-/format/mp4/-/size/x1080/preserve_ratio/-/quality/better/-/thumbs~5/",
+			testVideoID + "/video/-/format/mp4/-/size/x720/preserve_ratio/-/quality/normal/",
+			testVideoID + "/video/-/format/mp4/-/size/x480/preserve_ratio/-/quality/lighter/",
+			testVideoID + "/video/-/format/mp4/-/size/x360/preserve_ratio/-/quality/lightest/",
+		}, paths)
+
+		assert.Equal(t, testVideoID, manifest.Source)
+		require.NotNil(t, manifest.ThumbnailsGroupID)
+		assert.Equal(t, testThumbsID, *manifest.ThumbnailsGroupID)
+
+		require.Len(t, manifest.Renditions, 3)
+		for i, want := range []struct {
+			name, id string
+			token    int64
+		}{{"1080p", "out-1", 1}, {"720p", "out-2", 2}, {"360p", "out-4", 4}} {
+			r := manifest.Renditions[i]
+			assert.Equal(t, want.name, r.Name)
+			assert.Equal(t, want.id, r.ID)
+			assert.Equal(t, want.token, r.Token)
+		}
+	})
+}
+
+func TestConvertLadder_InvalidUUID(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL)
+	}), func(t *testing.T, srv *httptest.Server) {
+		for _, uuid := range []string{"", "not-a-uuid"} {
+			manifest, err := ConvertLadder(context.Background(), NewService(uctest.NewServerClient(srv)), uuid, LadderParams{})
+			require.ErrorIs(t, err, cdn.ErrInvalidUUID)
+			assert.NotContains(t, err.Error(), "rendition", "the UUID must be reported once")
+			assert.Empty(t, manifest.Renditions)
+		}
+	})
+}
@@ -0,0 +1,482 @@
+// Package export streams an inventory of project files into CSV or JSON
+// Lines.
+//
+// Exports read the files through file.List, so they can be resumed from a
+// checkpoint after an interruption. The checkpoint holds the output size
+// along with the list position, the output is truncated to it on resume:
+//
+//	out, err := os.OpenFile("files.csv", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+//	if err != nil {
+//		// handle error
+//	}
+//	defer out.Close()
+//
+//	n, err := export.Files(ctx, fileSvc, out, export.Params{
+//		Format:       export.FormatCSV,
+//		MetadataKeys: []string{"env"},
+//		AppData:      []addon.Name{addon.AddonClamAV},
+//		Checkpoint:   export.FileCheckpoint("files.csv.checkpoint"),
+//	})
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/addon"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// Format is an export output format
+type Format string
+
+// Supported output formats
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// Columns of file info fields
+const (
+	ColumnID               = "uuid"
+	ColumnOriginalFileName = "original_filename"
+	ColumnSize             = "size"
+	ColumnMimeType         = "mime_type"
+	ColumnIsImage          = "is_image"
+	ColumnIsReady          = "is_ready"
+	ColumnUploadedAt       = "datetime_uploaded"
+	ColumnStoredAt         = "datetime_stored"
+	ColumnRemovedAt        = "datetime_removed"
+	ColumnOriginalFileURL  = "original_file_url"
+	ColumnSource           = "source"
+)
+
+// Prefixes of the flattened metadata and add-on result columns
+const (
+	MetadataColumnPrefix = "metadata."
+	AppDataColumnPrefix  = "appdata."
+)
+
+// DefaultColumns are exported when Params.Columns is empty
+var DefaultColumns = []string{
+	ColumnID,
+	ColumnOriginalFileName,
+	ColumnSize,
+	ColumnMimeType,
+	ColumnUploadedAt,
+	ColumnStoredAt,
+	ColumnRemovedAt,
+}
+
+// DefaultCheckpointEvery is used when Params.CheckpointEvery is not set
+const DefaultCheckpointEvery = 1000
+
+// Params holds export params
+type Params struct {
+	// ListParams are passed to file.List. Include is set to "appdata"
+	// when AppData is not empty and Cursor is taken from the Checkpoint.
+	ListParams file.ListParams
+
+	// Format is an output format. Defaults to FormatCSV.
+	Format Format
+
+	// Columns are file info columns to export in the order given.
+	// Defaults to DefaultColumns.
+	Columns []string
+
+	// MetadataKeys are metadata keys exported as separate columns named
+	// with the MetadataColumnPrefix, e.g. "metadata.env".
+	MetadataKeys []string
+
+	// AppData are add-ons which results are exported as raw JSON columns
+	// named with the AppDataColumnPrefix, e.g. "appdata.remove_bg".
+	AppData []addon.Name
+
+	// Checkpoint stores the position the export is resumed from. When it
+	// holds a position, w must be the output of the interrupted export
+	// implementing Truncater, e.g. *os.File. The output is truncated to
+	// the saved size, dropping the rows written after the position was
+	// saved, and the export continues from there without writing the CSV
+	// header again. The output must hold nothing but the export. The
+	// first position is saved as soon as the header is written, so an
+	// export failing before the next one is resumed as well.
+	Checkpoint Checkpoint
+
+	// CheckpointEvery is the number of files written between saving the
+	// position to the Checkpoint. Defaults to DefaultCheckpointEvery.
+	CheckpointEvery int
+}
+
+// Position is a position of an export
+type Position struct {
+	// Cursor is the file list position, see file.List.Cursor
+	Cursor string `json:"cursor"`
+	// Offset is the output size at the Cursor
+	Offset int64 `json:"offset"`
+}
+
+// Checkpoint stores the position of an export
+type Checkpoint interface {
+	// Load returns the saved position or the zero Position if there is
+	// none
+	Load() (Position, error)
+	// Save saves the position
+	Save(Position) error
+}
+
+// Truncater is an output an export can be resumed into
+type Truncater interface {
+	io.Seeker
+	Truncate(size int64) error
+}
+
+// ErrNotTruncatable is returned by Files when resuming into an output not
+// implementing Truncater
+var ErrNotTruncatable = errors.New("export: resuming requires the output to implement Truncater")
+
+// FileCheckpoint is a Checkpoint stored in a file at the path
+type FileCheckpoint string
+
+// Load implements Checkpoint
+func (c FileCheckpoint) Load() (Position, error) {
+	var pos Position
+	data, err := os.ReadFile(string(c))
+	if errors.Is(err, os.ErrNotExist) {
+		return pos, nil
+	}
+	if err != nil {
+		return pos, err
+	}
+	err = json.Unmarshal(data, &pos)
+	return pos, err
+}
+
+// Save implements Checkpoint. The file is replaced atomically.
+func (c FileCheckpoint) Save(pos Position) error {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	tmp := string(c) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, string(c))
+}
+
+// Files writes every listed file as a row into w and returns the number
+// of rows written. The rows written before an error are flushed into w,
+// but the checkpoint is not advanced past them.
+func Files(
+	ctx context.Context,
+	svc file.Service,
+	w io.Writer,
+	params Params,
+) (int, error) {
+	columns := params.columns()
+	if err := validateColumns(columns); err != nil {
+		return 0, err
+	}
+
+	listParams := params.ListParams
+	if len(params.AppData) > 0 {
+		listParams.Include = ucare.String("appdata")
+	}
+
+	cw := &countingWriter{w: w}
+	resumed := false
+	if params.Checkpoint != nil {
+		pos, err := params.Checkpoint.Load()
+		if err != nil {
+			return 0, fmt.Errorf("loading checkpoint: %w", err)
+		}
+		if pos.Cursor != "" {
+			if err := truncate(w, pos.Offset); err != nil {
+				return 0, err
+			}
+			listParams.Cursor = &pos.Cursor
+			cw.n = pos.Offset
+			resumed = true
+		}
+	}
+
+	list, err := svc.List(ctx, listParams)
+	if err != nil {
+		return 0, err
+	}
+	defer list.Close()
+
+	out, err := newWriter(params.Format, cw, columns)
+	if err != nil {
+		return 0, err
+	}
+	if !resumed {
+		if err := out.header(); err != nil {
+			return 0, err
+		}
+		// a rerun failing before the first periodic checkpoint must not
+		// write the rows again after the ones already written
+		if err := checkpoint(out, cw, params.Checkpoint, list); err != nil {
+			return 0, err
+		}
+	}
+
+	every := params.CheckpointEvery
+	if every <= 0 {
+		every = DefaultCheckpointEvery
+	}
+
+	n := 0
+	for list.Next() {
+		info, err := list.ReadResult()
+		if err == nil {
+			err = out.row(rowValues(info, columns))
+		}
+		if err != nil {
+			// rows past the saved position are truncated on resume
+			_ = out.flush()
+			return n, err
+		}
+		n++
+
+		if n%every == 0 {
+			if err := checkpoint(out, cw, params.Checkpoint, list); err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, checkpoint(out, cw, params.Checkpoint, list)
+}
+
+// truncate drops the output written after the offset
+func truncate(w io.Writer, offset int64) error {
+	t, ok := w.(Truncater)
+	if !ok {
+		return ErrNotTruncatable
+	}
+	if err := t.Truncate(offset); err != nil {
+		return fmt.Errorf("truncating output: %w", err)
+	}
+	if _, err := t.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("truncating output: %w", err)
+	}
+	return nil
+}
+
+// checkpoint flushes the output before saving the position, so the saved
+// position never gets ahead of the written rows
+func checkpoint(out writer, cw *countingWriter, c Checkpoint, list *file.List) error {
+	if err := out.flush(); err != nil {
+		return err
+	}
+	if c == nil {
+		return nil
+	}
+	if err := c.Save(Position{Cursor: list.Cursor(), Offset: cw.n}); err != nil {
+		return fmt.Errorf("saving checkpoint: %w", err)
+	}
+	return nil
+}
+
+// countingWriter counts the output size
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (p Params) columns() []string {
+	columns := p.Columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	columns = slices.Clone(columns)
+	for _, k := range p.MetadataKeys {
+		columns = append(columns, MetadataColumnPrefix+k)
+	}
+	for _, name := range p.AppData {
+		columns = append(columns, AppDataColumnPrefix+string(name))
+	}
+	return columns
+}
+
+var fileColumns = []string{
+	ColumnID,
+	ColumnOriginalFileName,
+	ColumnSize,
+	ColumnMimeType,
+	ColumnIsImage,
+	ColumnIsReady,
+	ColumnUploadedAt,
+	ColumnStoredAt,
+	ColumnRemovedAt,
+	ColumnOriginalFileURL,
+	ColumnSource,
+}
+
+func validateColumns(columns []string) error {
+	for _, c := range columns {
+		if slices.Contains(fileColumns, c) ||
+			strings.HasPrefix(c, MetadataColumnPrefix) ||
+			strings.HasPrefix(c, AppDataColumnPrefix) {
+			continue
+		}
+		return fmt.Errorf("export: unknown column %q", c)
+	}
+	return nil
+}
+
+// rowValues returns column values as strings, numbers, booleans, raw JSON
+// or nil for absent values
+func rowValues(info *file.Info, columns []string) []any {
+	values := make([]any, len(columns))
+	for i, c := range columns {
+		switch {
+		case strings.HasPrefix(c, MetadataColumnPrefix):
+			if v, ok := info.Metadata[strings.TrimPrefix(c, MetadataColumnPrefix)]; ok {
+				values[i] = v
+			}
+		case strings.HasPrefix(c, AppDataColumnPrefix):
+			if v, ok := info.AppData[strings.TrimPrefix(c, AppDataColumnPrefix)]; ok {
+				values[i] = v
+			}
+		default:
+			values[i] = fileValue(info, c)
+		}
+	}
+	return values
+}
+
+func fileValue(info *file.Info, column string) any {
+	switch column {
+	case ColumnID:
+		return info.ID
+	case ColumnOriginalFileName:
+		return info.OriginalFileName
+	case ColumnSize:
+		return info.Size
+	case ColumnMimeType:
+		return info.MimeType
+	case ColumnIsImage:
+		return info.IsImage
+	case ColumnIsReady:
+		return info.IsReady
+	case ColumnUploadedAt:
+		return timeValue(info.UploadedAt)
+	case ColumnStoredAt:
+		return timeValue(info.StoredAt)
+	case ColumnRemovedAt:
+		return timeValue(info.RemovedAt)
+	case ColumnOriginalFileURL:
+		if info.OriginalFileURL != nil {
+			return *info.OriginalFileURL
+		}
+	case ColumnSource:
+		if info.Source != nil {
+			return *info.Source
+		}
+	}
+	return nil
+}
+
+func timeValue(t *config.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type writer interface {
+	header() error
+	row([]any) error
+	flush() error
+}
+
+func newWriter(format Format, w io.Writer, columns []string) (writer, error) {
+	switch format {
+	case "", FormatCSV:
+		return &csvWriter{csv.NewWriter(w), columns}, nil
+	case FormatJSONL:
+		return &jsonlWriter{bufio.NewWriter(w), columns}, nil
+	}
+	return nil, fmt.Errorf("export: unsupported format %q", format)
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (w *csvWriter) header() error { return w.w.Write(w.columns) }
+
+func (w *csvWriter) row(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case uint64:
+			record[i] = strconv.FormatUint(v, 10)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case json.RawMessage:
+			record[i] = string(v)
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (w *jsonlWriter) header() error { return nil }
+
+// row writes values as a JSON object keeping the keys in the column order
+func (w *jsonlWriter) row(values []any) error {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, c := range w.columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(c)
+		val, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(val)
+	}
+	b.WriteString("}\n")
+	_, err := w.w.WriteString(b.String())
+	return err
+}
+
+func (w *jsonlWriter) flush() error { return w.w.Flush() }
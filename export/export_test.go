@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/addon"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+func testFiles(n int) []file.Info {
+	uploaded := &config.Time{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	files := make([]file.Info, n)
+	for i := range files {
+		files[i] = file.Info{
+			BasicFileInfo: file.BasicFileInfo{
+				ID:               fmt.Sprintf("f%d", i+1),
+				OriginalFileName: fmt.Sprintf("photo, %d.jpg", i+1),
+				Size:             uint64(1000 * (i + 1)),
+				MimeType:         "image/jpeg",
+			},
+			UploadedAt: uploaded,
+			Metadata:   map[string]string{"env": "prod"},
+			AppData: map[string]json.RawMessage{
+				string(addon.AddonRemoveBG): json.RawMessage(`{"data": {"foreground_type": "person"}}`),
+			},
+		}
+	}
+	return files
+}
+
+// serveFiles serves files in pages of two
+func serveFiles(t *testing.T, files []file.Info) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+2, len(files))
+		resp := map[string]any{"next": nil, "results": files[offset:end]}
+		if end < len(files) {
+			resp["next"] = fmt.Sprintf("http://%s/files/?offset=%d", r.Host, end)
+		}
+		uctest.RespondJSON(t, w, resp)
+	})
+}
+
+func TestFiles_CSV(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, serveFiles(t, testFiles(3)), func(t *testing.T, srv *httptest.Server) {
+		var out bytes.Buffer
+		n, err := Files(context.Background(), file.NewService(uctest.NewServerClient(srv)), &out, Params{
+			Columns:      []string{ColumnID, ColumnOriginalFileName, ColumnSize, ColumnUploadedAt, ColumnStoredAt},
+			MetadataKeys: []string{"env", "missing"},
+			AppData:      []addon.Name{addon.AddonRemoveBG},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, "uuid,original_filename,size,datetime_uploaded,datetime_stored,metadata.env,metadata.missing,appdata.remove_bg", lines[0])
+		assert.Equal(t, `f1,"photo, 1.jpg",1000,2024-05-01T10:00:00Z,,prod,,"{""data"":{""foreground_type"":""person""}}"`, lines[1])
+	})
+}
+
+func TestFiles_JSONL(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, serveFiles(t, testFiles(1)), func(t *testing.T, srv *httptest.Server) {
+		var out bytes.Buffer
+		_, err := Files(context.Background(), file.NewService(uctest.NewServerClient(srv)), &out, Params{
+			Format:       FormatJSONL,
+			Columns:      []string{ColumnID, ColumnSize, ColumnStoredAt},
+			MetadataKeys: []string{"env"},
+			AppData:      []addon.Name{addon.AddonRemoveBG},
+		})
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"uuid":"f1","size":1000,"datetime_stored":null,"metadata.env":"prod","appdata.remove_bg":{"data":{"foreground_type":"person"}}}`+"\n",
+			out.String(),
+		)
+	})
+}
+
+func TestFiles_UnknownColumn(t *testing.T) {
+	t.Parallel()
+
+	_, err := Files(context.Background(), file.NewService(&uctest.Client{}), &bytes.Buffer{}, Params{
+		Columns: []string{"color"},
+	})
+	assert.ErrorContains(t, err, `unknown column "color"`)
+}
+
+type memCheckpoint struct{ pos Position }
+
+func (c *memCheckpoint) Load() (Position, error) { return c.pos, nil }
+
+func (c *memCheckpoint) Save(pos Position) error {
+	c.pos = pos
+	return nil
+}
+
+// failingWriter fails once the output contains the marker
+type failingWriter struct {
+	bytes.Buffer
+	marker string
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.marker != "" && bytes.Contains(p, []byte(w.marker)) {
+		return 0, errors.New("disk is full")
+	}
+	return w.Buffer.Write(p)
+}
+
+func (w *failingWriter) Truncate(size int64) error {
+	w.Buffer.Truncate(int(size))
+	return nil
+}
+
+func (w *failingWriter) Seek(offset int64, whence int) (int64, error) {
+	return int64(w.Len()), nil
+}
+
+func TestFiles_Resume(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, serveFiles(t, testFiles(5)), func(t *testing.T, srv *httptest.Server) {
+		svc := file.NewService(uctest.NewServerClient(srv))
+		cp := &memCheckpoint{}
+		params := Params{Columns: []string{ColumnID}, Checkpoint: cp, CheckpointEvery: 2}
+
+		out := &failingWriter{marker: "f4"}
+		n, err := Files(context.Background(), svc, out, params)
+		require.Error(t, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, "uuid\nf1\nf2\n", out.String())
+		assert.Equal(t, Position{Cursor: cp.pos.Cursor, Offset: 11}, cp.pos)
+
+		// a partial row left by a crash after the checkpoint
+		out.WriteString("f3\nf")
+		out.marker = ""
+		n, err = Files(context.Background(), svc, out, params)
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, "uuid\nf1\nf2\nf3\nf4\nf5\n", out.String())
+	})
+}
+
+// crashingCheckpoint keeps the first saved positions only, as if the
+// process crashed right after saving them while the rows kept being flushed
+type crashingCheckpoint struct {
+	memCheckpoint
+	saves, keep int
+}
+
+func (c *crashingCheckpoint) Save(pos Position) error {
+	c.saves++
+	if c.saves <= c.keep {
+		c.pos = pos
+	}
+	return nil
+}
+
+func TestFiles_ResumeFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  Format
+		keep    int
+		resumed int
+		want    string
+	}{
+		{
+			name:    "after_checkpoint",
+			format:  FormatJSONL,
+			keep:    2, // the initial position and the one after f2
+			resumed: 3,
+			want:    "{\"uuid\":\"f1\"}\n{\"uuid\":\"f2\"}\n{\"uuid\":\"f3\"}\n{\"uuid\":\"f4\"}\n{\"uuid\":\"f5\"}\n",
+		},
+		{
+			name:    "before_first_checkpoint",
+			format:  FormatCSV,
+			keep:    1, // the initial position only
+			resumed: 5,
+			want:    "uuid\nf1\nf2\nf3\nf4\nf5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uctest.WithHTTPServer(t, serveFiles(t, testFiles(5)), func(t *testing.T, srv *httptest.Server) {
+				svc := file.NewService(uctest.NewServerClient(srv))
+				path := t.TempDir() + "/files"
+				cp := &crashingCheckpoint{keep: tt.keep}
+				params := Params{Format: tt.format, Columns: []string{ColumnID}, Checkpoint: cp, CheckpointEvery: 2}
+
+				export := func() int {
+					f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+					require.NoError(t, err)
+					defer func() { require.NoError(t, f.Close()) }()
+					n, err := Files(context.Background(), svc, f, params)
+					require.NoError(t, err)
+					return n
+				}
+
+				assert.Equal(t, 5, export())
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+				require.NoError(t, err)
+				_, err = f.WriteString("partial")
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				assert.Equal(t, tt.resumed, export())
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, string(data))
+			})
+		})
+	}
+}
+
+func TestFiles_ResumeNotTruncatable(t *testing.T) {
+	t.Parallel()
+
+	cp := &memCheckpoint{pos: Position{Cursor: "cursor", Offset: 10}}
+	_, err := Files(context.Background(), file.NewService(&uctest.Client{}), &bytes.Buffer{}, Params{
+		Checkpoint: cp,
+	})
+	assert.ErrorIs(t, err, ErrNotTruncatable)
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	cp := FileCheckpoint(t.TempDir() + "/export.checkpoint")
+	pos, err := cp.Load()
+	require.NoError(t, err)
+	assert.Zero(t, pos)
+
+	require.NoError(t, cp.Save(Position{Cursor: "cursor", Offset: 42}))
+	pos, err = cp.Load()
+	require.NoError(t, err)
+	assert.Equal(t, Position{Cursor: "cursor", Offset: 42}, pos)
+}
@@ -0,0 +1,302 @@
+// Package fanout runs a function over every result of a paginated list with
+// bounded concurrency.
+//
+// It works on top of file.List and group.List:
+//
+//	list, err := fileSvc.List(ctx, file.ListParams{})
+//	if err != nil {
+//		// handle error
+//	}
+//	err = fanout.Run(ctx, list, func(ctx context.Context, info *file.Info) error {
+//		_, err := metadataSvc.Set(ctx, info.ID, "checked", "true")
+//		return err
+//	}, fanout.Options[file.Info]{Concurrency: 8})
+package fanout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is used when Options.Concurrency is not set
+const DefaultConcurrency = 4
+
+// Lister is a list of results read one by one, e.g. *file.List or
+// *group.List
+type Lister[T any] interface {
+	Next() bool
+	ReadResult() (*T, error)
+}
+
+// Func is called for every result of a list
+type Func[T any] func(ctx context.Context, item *T) error
+
+// Options holds fan-out settings
+type Options[T any] struct {
+	// Concurrency limits the number of Func calls running at once.
+	// Defaults to DefaultConcurrency.
+	Concurrency int
+
+	// ContinueOnError keeps processing the rest of the results when Func
+	// fails. By default the first failure cancels the context passed to
+	// running Func calls and stops reading the list.
+	ContinueOnError bool
+
+	// Ordered makes OnDone called in the list order rather than in the
+	// order Func calls complete. Func calls do not run more than
+	// Concurrency results ahead of the next result to report, so a slow
+	// call holds the following ones back.
+	Ordered bool
+
+	// OnDone is called after Func completes for a result with the result
+	// index in the list and the error Func returned, if any.
+	OnDone func(index int, item *T, err error)
+
+	// Progress is called after every completed Func call.
+	Progress func(Progress)
+}
+
+// Progress reports fan-out progress
+type Progress struct {
+	// Done is a number of completed Func calls, including failed ones
+	Done int
+	// Failed is a number of failed Func calls
+	Failed int
+	// Total is a total number of results in the list, if the list
+	// reports it (like file.List does), zero otherwise
+	Total uint64
+}
+
+// ItemError holds the Func error for a single result
+type ItemError[T any] struct {
+	Index int
+	Item  *T
+	Err   error
+}
+
+func (e ItemError[T]) Error() string {
+	return fmt.Sprintf("fanout: result %d: %s", e.Index, e.Err)
+}
+
+func (e ItemError[T]) Unwrap() error { return e.Err }
+
+// Errors aggregates the errors of failed Func calls in the list order
+type Errors[T any] []ItemError[T]
+
+func (e Errors[T]) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ie := range e {
+		msgs = append(msgs, ie.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of failed Func calls
+func (e Errors[T]) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, ie := range e {
+		errs = append(errs, ie)
+	}
+	return errs
+}
+
+// ErrReadList wraps the error the list returned while being read
+var ErrReadList = errors.New("fanout: reading list")
+
+type job[T any] struct {
+	index int
+	item  *T
+}
+
+type done[T any] struct {
+	job[T]
+	err error
+}
+
+// Run calls fn for every result read from the list, running up to
+// opts.Concurrency calls at once. It returns after all started calls
+// complete.
+//
+// The returned error joins the list read error (wrapped with
+// ErrReadList), the context error and Errors holding failed calls.
+// Results left unprocessed after a failure in fail-fast mode or after
+// the context is done are neither passed to fn nor reported.
+func Run[T any](
+	ctx context.Context,
+	list Lister[T],
+	fn Func[T],
+	opts Options[T],
+) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// in the ordered mode a slot is taken for every result read and
+	// given back when the result is reported, which bounds the results
+	// waiting for their predecessors
+	var window chan struct{}
+	if opts.Ordered {
+		window = make(chan struct{}, concurrency)
+	}
+
+	jobs := make(chan job[T])
+	var readErr error
+	go func() {
+		defer close(jobs)
+		for i := 0; list.Next(); i++ {
+			if window != nil {
+				select {
+				case window <- struct{}{}:
+				case <-runCtx.Done():
+					return
+				}
+			}
+			item, err := list.ReadResult()
+			if err != nil {
+				readErr = fmt.Errorf("%w: %w", ErrReadList, err)
+				cancel()
+				return
+			}
+			select {
+			case jobs <- job[T]{i, item}:
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan done[T])
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if runCtx.Err() != nil {
+					continue
+				}
+				results <- done[T]{j, fn(runCtx, j.item)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	c := collector[T]{opts: opts, window: window}
+	if t, ok := list.(interface{ Total() uint64 }); ok {
+		c.progress.Total = t.Total()
+	}
+	for d := range results {
+		if d.err != nil && !opts.ContinueOnError {
+			cancel()
+		}
+		c.add(d)
+	}
+	c.flush()
+
+	return errors.Join(readErr, ctx.Err(), c.errs())
+}
+
+// collector reports completed calls, it is used by a single goroutine
+type collector[T any] struct {
+	opts     Options[T]
+	progress Progress
+	failed   Errors[T]
+
+	next    int // index of the next result to report in the ordered mode
+	pending map[int]done[T]
+	window  chan struct{}
+}
+
+func (c *collector[T]) add(d done[T]) {
+	c.progress.Done++
+	if d.err != nil {
+		c.progress.Failed++
+		c.failed = append(c.failed, ItemError[T]{d.index, d.item, d.err})
+	}
+	if c.opts.Progress != nil {
+		c.opts.Progress(c.progress)
+	}
+
+	if !c.opts.Ordered {
+		c.report(d)
+		return
+	}
+
+	if c.pending == nil {
+		c.pending = make(map[int]done[T])
+	}
+	c.pending[d.index] = d
+	for {
+		p, ok := c.pending[c.next]
+		if !ok {
+			return
+		}
+		delete(c.pending, c.next)
+		c.report(p)
+		c.next++
+		<-c.window
+	}
+}
+
+// flush reports results still waiting for skipped predecessors
+func (c *collector[T]) flush() {
+	for len(c.pending) > 0 {
+		if p, ok := c.pending[c.next]; ok {
+			delete(c.pending, c.next)
+			c.report(p)
+		}
+		c.next++
+	}
+}
+
+func (c *collector[T]) report(d done[T]) {
+	if c.opts.OnDone != nil {
+		c.opts.OnDone(d.index, d.item, d.err)
+	}
+}
+
+func (c *collector[T]) errs() error {
+	if len(c.failed) == 0 {
+		return nil
+	}
+	slices.SortFunc(c.failed, func(a, b ItemError[T]) int {
+		return a.Index - b.Index
+	})
+	return c.failed
+}
+
+// Slice returns a Lister over the items, e.g. to fan out over the IDs
+// collected elsewhere
+func Slice[T any](items []T) Lister[T] {
+	return &sliceLister[T]{items: items}
+}
+
+type sliceLister[T any] struct {
+	items []T
+	at    int
+}
+
+func (l *sliceLister[T]) Next() bool { return l.at < len(l.items) }
+
+func (l *sliceLister[T]) ReadResult() (*T, error) {
+	if !l.Next() {
+		return nil, errors.New("fanout: no items left to read")
+	}
+	item := &l.items[l.at]
+	l.at++
+	return item, nil
+}
+
+// Total implements the list total reporting for Progress
+func (l *sliceLister[T]) Total() uint64 { return uint64(len(l.items)) }
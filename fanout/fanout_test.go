@@ -0,0 +1,176 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func numbers(n int) []int {
+	nums := make([]int, n)
+	for i := range nums {
+		nums[i] = i
+	}
+	return nums
+}
+
+func TestRun_BoundedConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning, calls atomic.Int32
+	err := Run(context.Background(), Slice(numbers(50)), func(ctx context.Context, n *int) error {
+		cur := running.Add(1)
+		defer running.Add(-1)
+		for {
+			prev := maxRunning.Load()
+			if cur <= prev || maxRunning.CompareAndSwap(prev, cur) {
+				break
+			}
+		}
+		calls.Add(1)
+		time.Sleep(time.Millisecond)
+		return nil
+	}, Options[int]{Concurrency: 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), calls.Load())
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+
+	errOdd := errors.New("odd")
+	failOdd := func(ctx context.Context, n *int) error {
+		if *n%2 == 1 {
+			return errOdd
+		}
+		return nil
+	}
+
+	t.Run("fail_fast", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		err := Run(context.Background(), Slice(numbers(100)), func(ctx context.Context, n *int) error {
+			calls.Add(1)
+			return failOdd(ctx, n)
+		}, Options[int]{Concurrency: 1})
+
+		var failed Errors[int]
+		require.ErrorAs(t, err, &failed)
+		require.Len(t, failed, 1)
+		assert.Equal(t, 1, failed[0].Index)
+		assert.ErrorIs(t, err, errOdd)
+		assert.Less(t, calls.Load(), int32(100))
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		t.Parallel()
+
+		var last Progress
+		err := Run(context.Background(), Slice(numbers(10)), failOdd, Options[int]{
+			Concurrency:     4,
+			ContinueOnError: true,
+			Progress:        func(p Progress) { last = p },
+		})
+
+		var failed Errors[int]
+		require.ErrorAs(t, err, &failed)
+		indexes := make([]int, 0, len(failed))
+		for _, f := range failed {
+			indexes = append(indexes, f.Index)
+			assert.Equal(t, f.Index, *f.Item)
+		}
+		assert.Equal(t, []int{1, 3, 5, 7, 9}, indexes)
+		assert.Equal(t, Progress{Done: 10, Failed: 5, Total: 10}, last)
+	})
+}
+
+type brokenList struct{ read int }
+
+func (l *brokenList) Next() bool { return true }
+
+func (l *brokenList) ReadResult() (*int, error) {
+	if l.read == 3 {
+		return nil, errors.New("boom")
+	}
+	l.read++
+	return &l.read, nil
+}
+
+func TestRun_ReadError(t *testing.T) {
+	t.Parallel()
+
+	err := Run(context.Background(), &brokenList{}, func(ctx context.Context, n *int) error {
+		return nil
+	}, Options[int]{})
+	assert.ErrorIs(t, err, ErrReadList)
+}
+
+func TestRun_Ordered(t *testing.T) {
+	t.Parallel()
+
+	var reported []int
+	err := Run(context.Background(), Slice(numbers(20)), func(ctx context.Context, n *int) error {
+		time.Sleep(time.Duration(20-*n) * 100 * time.Microsecond)
+		return nil
+	}, Options[int]{
+		Concurrency: 5,
+		Ordered:     true,
+		OnDone:      func(index int, _ *int, _ error) { reported = append(reported, index) },
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, numbers(20), reported)
+}
+
+func TestRun_OrderedWindow(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var started atomic.Int32
+	go func() {
+		// the first call is slow, the rest must not run far ahead of it
+		assert.Never(t, func() bool { return started.Load() > 3 }, 50*time.Millisecond, time.Millisecond)
+		close(release)
+	}()
+
+	var reported []int
+	err := Run(context.Background(), Slice(numbers(20)), func(ctx context.Context, n *int) error {
+		started.Add(1)
+		if *n == 0 {
+			<-release
+		}
+		return nil
+	}, Options[int]{
+		Concurrency: 3,
+		Ordered:     true,
+		OnDone:      func(index int, _ *int, _ error) { reported = append(reported, index) },
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), started.Load())
+	assert.Equal(t, numbers(20), reported)
+}
+
+func TestRun_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	err := Run(ctx, Slice(numbers(10)), func(ctx context.Context, n *int) error {
+		calls.Add(1)
+		return nil
+	}, Options[int]{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(0), calls.Load())
+}
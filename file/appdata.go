@@ -0,0 +1,47 @@
+package file
+
+import (
+	"encoding/json"
+
+	"github.com/uploadcare/uploadcare-go/v2/addon"
+)
+
+// ClamAV returns the virus scan result. The bool is false if the file has
+// not been scanned or AppData has not been requested.
+func (v Info) ClamAV() (addon.ClamAVResult, bool, error) {
+	return appData[addon.ClamAVResult](v, addon.AddonClamAV)
+}
+
+// RekognitionLabels returns labels detected in the image. The bool is false
+// if the add-on has not been executed or AppData has not been requested.
+func (v Info) RekognitionLabels() (addon.RekognitionLabelsResult, bool, error) {
+	return appData[addon.RekognitionLabelsResult](v, addon.AddonRekognitionLabels)
+}
+
+// RekognitionModeration returns unsafe content labels detected in the
+// image. The bool is false if the add-on has not been executed or AppData
+// has not been requested.
+func (v Info) RekognitionModeration() (addon.RekognitionModerationResult, bool, error) {
+	return appData[addon.RekognitionModerationResult](v, addon.AddonRekognitionModeration)
+}
+
+// RemoveBG returns the background removal result. The bool is false if the
+// add-on has not been executed or AppData has not been requested.
+func (v Info) RemoveBG() (addon.RemoveBGResult, bool, error) {
+	return appData[addon.RemoveBGResult](v, addon.AddonRemoveBG)
+}
+
+// AddonResult returns the result of any add-on with the data left as raw
+// JSON. Use it for add-ons with no typed accessor.
+func (v Info) AddonResult(name addon.Name) (addon.RawResult, bool, error) {
+	return appData[addon.RawResult](v, name)
+}
+
+func appData[T any](info Info, name addon.Name) (res T, ok bool, err error) {
+	raw, ok := info.AppData[string(name)]
+	if !ok {
+		return
+	}
+	err = json.Unmarshal(raw, &res)
+	return
+}
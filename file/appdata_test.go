@@ -0,0 +1,120 @@
+package file
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/addon"
+)
+
+const appDataJSON = `{
+	"uuid": "` + testFileUUID + `",
+	"appdata": {
+		"uc_clamav_virus_scan": {
+			"data": {"infected": true, "infected_with": "Win.Test.EICAR_HDB-1"},
+			"version": "0.104.2",
+			"datetime_created": "2021-09-21T11:24:33.159663Z",
+			"datetime_updated": "2021-09-21T11:24:33.159663Z"
+		},
+		"aws_rekognition_detect_labels": {
+			"data": {
+				"LabelModelVersion": "2.0",
+				"Labels": [{
+					"Confidence": 93.41,
+					"Instances": [{
+						"BoundingBox": {"Height": 0.5, "Left": 0.1, "Top": 0.2, "Width": 0.3},
+						"Confidence": 93.41
+					}],
+					"Name": "Dog",
+					"Parents": [{"Name": "Animal"}]
+				}]
+			},
+			"version": "2016-06-27",
+			"datetime_created": "2021-09-21T11:25:31.259763Z",
+			"datetime_updated": "2021-09-21T11:27:33.359763Z"
+		},
+		"aws_rekognition_detect_moderation_labels": {
+			"data": {
+				"ModerationModelVersion": "6.0",
+				"ModerationLabels": [
+					{"Confidence": 93.41, "Name": "Weapons", "ParentName": "Violence"},
+					{"Confidence": 40.1, "Name": "Smoking", "ParentName": "Drugs"}
+				]
+			},
+			"version": "2016-06-27",
+			"datetime_created": "2023-02-21T11:25:31.259763Z",
+			"datetime_updated": "2023-02-21T11:27:33.359763Z"
+		},
+		"remove_bg": {
+			"data": {"foreground_type": "person"},
+			"version": "1.0",
+			"datetime_created": "2021-07-25T12:24:33.159663Z",
+			"datetime_updated": "2021-07-25T12:24:33.159663Z"
+		},
+		"custom_addon": {
+			"data": {"answer": 42},
+			"version": "1"
+		}
+	}
+}`
+
+func TestInfo_AppData(t *testing.T) {
+	t.Parallel()
+
+	var info Info
+	require.NoError(t, json.Unmarshal([]byte(appDataJSON), &info))
+
+	clamav, ok, err := info.ClamAV()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, clamav.IsInfected())
+	assert.Equal(t, "Win.Test.EICAR_HDB-1", clamav.Threat())
+	assert.Equal(t, "0.104.2", clamav.Version)
+	require.NotNil(t, clamav.CreatedAt)
+	assert.Equal(t, 2021, clamav.CreatedAt.Year())
+
+	labels, ok, err := info.RekognitionLabels()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Dog"}, labels.Names())
+	assert.True(t, labels.HasLabel("Dog", 90))
+	assert.False(t, labels.HasLabel("Dog", 95))
+	dog, _ := labels.Label("Dog")
+	assert.Equal(t, 0.3, dog.Instances[0].BoundingBox.Width)
+	assert.Equal(t, "Animal", dog.Parents[0].Name)
+
+	moderation, ok, err := info.RekognitionModeration()
+	require.NoError(t, err)
+	require.True(t, ok)
+	flagged := moderation.Flagged(50)
+	require.Len(t, flagged, 1)
+	assert.Equal(t, "Violence", flagged[0].ParentName)
+
+	removeBG, ok, err := info.RemoveBG()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "person", removeBG.ForegroundType())
+
+	custom, ok, err := info.AddonResult("custom_addon")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"answer": 42}`, string(custom.Data))
+}
+
+func TestInfo_AppDataMissing(t *testing.T) {
+	t.Parallel()
+
+	info := Info{AppData: map[string]json.RawMessage{
+		string(addon.AddonRemoveBG): json.RawMessage(`{"data": []}`),
+	}}
+
+	_, ok, err := info.ClamAV()
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	_, ok, err = info.RemoveBG()
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
@@ -2,8 +2,12 @@ package file
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 
+	"github.com/uploadcare/uploadcare-go/v2/fanout"
 	"github.com/uploadcare/uploadcare-go/v2/internal/codec"
 )
 
@@ -63,3 +67,158 @@ type BatchInfo struct {
 	// Results describes successfully operated files
 	Results []Info `json:"result"`
 }
+
+// MaxBatchSize is the maximum number of files a single batch request accepts
+const MaxBatchSize = 100
+
+// BatchAllParams holds params for the BatchStoreAll and BatchDeleteAll
+// functions
+type BatchAllParams struct {
+	// ChunkSize is the number of files per single batch request.
+	// Defaults to and is capped at MaxBatchSize.
+	ChunkSize int
+
+	// Concurrency limits the number of batch requests running at once.
+	// Defaults to fanout.DefaultConcurrency.
+	Concurrency int
+}
+
+// BatchChunkError holds the error of a failed batch request
+type BatchChunkError struct {
+	// IDs are the file IDs sent within the failed request
+	IDs []string
+	Err error
+}
+
+func (e BatchChunkError) Error() string {
+	return fmt.Sprintf("batch of %d files: %s", len(e.IDs), e.Err)
+}
+
+func (e BatchChunkError) Unwrap() error { return e.Err }
+
+// BatchAllError is returned by BatchStoreAll and BatchDeleteAll when some
+// of the batch requests fail. Files from the failed requests are neither in
+// BatchInfo.Results nor in BatchInfo.Problems, so they can be retried.
+type BatchAllError struct {
+	Chunks []BatchChunkError
+}
+
+func (e *BatchAllError) Error() string {
+	return fmt.Sprintf(
+		"%d of the batch requests failed, first error: %s",
+		len(e.Chunks),
+		e.Chunks[0].Err,
+	)
+}
+
+// Unwrap returns the errors of failed batch requests
+func (e *BatchAllError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Chunks))
+	for _, c := range e.Chunks {
+		errs = append(errs, c)
+	}
+	return errs
+}
+
+// FailedIDs returns IDs of the files from the failed batch requests
+func (e *BatchAllError) FailedIDs() []string {
+	var ids []string
+	for _, c := range e.Chunks {
+		ids = append(ids, c.IDs...)
+	}
+	return ids
+}
+
+// BatchStoreAll stores any number of files with svc.BatchStore, splitting
+// ids into batch requests of up to MaxBatchSize files and running them
+// concurrently. Results and problems of all requests are merged into a
+// single BatchInfo. If some requests fail, BatchInfo still holds the
+// successful ones and the returned *BatchAllError tells which files have
+// not been handled.
+//
+// Example usage:
+//
+//	data, err := file.BatchStoreAll(ctx, fileSvc, ids, nil)
+func BatchStoreAll(
+	ctx context.Context,
+	svc Service,
+	ids []string,
+	params *BatchAllParams,
+) (BatchInfo, error) {
+	return batchAll(ctx, ids, params, svc.BatchStore)
+}
+
+// BatchDeleteAll deletes any number of files with svc.BatchDelete the same
+// way BatchStoreAll stores them.
+func BatchDeleteAll(
+	ctx context.Context,
+	svc Service,
+	ids []string,
+	params *BatchAllParams,
+) (BatchInfo, error) {
+	return batchAll(ctx, ids, params, svc.BatchDelete)
+}
+
+type batchChunk struct {
+	ids  []string
+	data BatchInfo
+	err  error
+	done bool
+}
+
+func batchAll(
+	ctx context.Context,
+	ids []string,
+	params *BatchAllParams,
+	op func(context.Context, []string) (BatchInfo, error),
+) (BatchInfo, error) {
+	if params == nil {
+		params = &BatchAllParams{}
+	}
+	size := params.ChunkSize
+	if size <= 0 || size > MaxBatchSize {
+		size = MaxBatchSize
+	}
+
+	var chunks []batchChunk
+	for c := range slices.Chunk(ids, size) {
+		chunks = append(chunks, batchChunk{ids: c})
+	}
+
+	// failures are collected from the chunks below, including the ones
+	// skipped because the context is done
+	_ = fanout.Run(
+		ctx,
+		fanout.Slice(chunks),
+		func(ctx context.Context, c *batchChunk) error {
+			log.Debugf("batch request for %d files", len(c.ids))
+			c.data, c.err = op(ctx, c.ids)
+			c.done = true
+			return c.err
+		},
+		fanout.Options[batchChunk]{
+			Concurrency:     params.Concurrency,
+			ContinueOnError: true,
+		},
+	)
+
+	data := BatchInfo{Problems: map[string]string{}}
+	var failed []BatchChunkError
+	for _, c := range chunks {
+		if !c.done {
+			// the context is done before the request has been made
+			c.err = ctx.Err()
+		}
+		if c.err != nil {
+			failed = append(failed, BatchChunkError{IDs: c.ids, Err: c.err})
+			continue
+		}
+		maps.Copy(data.Problems, c.data.Problems)
+		data.Results = append(data.Results, c.data.Results...)
+	}
+
+	if len(failed) > 0 {
+		return data, &BatchAllError{Chunks: failed}
+	}
+	return data, nil
+}
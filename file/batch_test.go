@@ -0,0 +1,85 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+func batchIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = "id-" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+func TestBatchStoreAll(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, http.MethodPut, r.Method)
+
+		var ids []string
+		require.NoError(t, json.Unmarshal(uctest.ReadBody(t, r), &ids))
+		assert.LessOrEqual(t, len(ids), MaxBatchSize)
+
+		data := BatchInfo{Problems: map[string]string{}}
+		for _, id := range ids {
+			if id == "id-42" {
+				data.Problems[id] = "Missing in the project"
+				continue
+			}
+			data.Results = append(data.Results, Info{BasicFileInfo: BasicFileInfo{ID: id}})
+		}
+		uctest.RespondJSON(t, w, data)
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		data, err := BatchStoreAll(context.Background(), svc, batchIDs(250), &BatchAllParams{Concurrency: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(3), requests.Load())
+		assert.Len(t, data.Results, 249)
+		assert.Equal(t, map[string]string{"id-42": "Missing in the project"}, data.Problems)
+	})
+}
+
+func TestBatchDeleteAll_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		var ids []string
+		require.NoError(t, json.Unmarshal(uctest.ReadBody(t, r), &ids))
+		if ids[0] == "id-10" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var data BatchInfo
+		for _, id := range ids {
+			data.Results = append(data.Results, Info{BasicFileInfo: BasicFileInfo{ID: id}})
+		}
+		uctest.RespondJSON(t, w, data)
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		data, err := BatchDeleteAll(context.Background(), svc, batchIDs(25), &BatchAllParams{ChunkSize: 10})
+
+		var batchErr *BatchAllError
+		require.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, batchIDs(20)[10:], batchErr.FailedIDs())
+		assert.Len(t, data.Results, 15)
+	})
+}
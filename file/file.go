@@ -172,7 +172,7 @@ type ImageInfo struct {
 	Width uint64 `json:"width"`
 
 	// Orientation is image orientation from EXIF
-	// could be *string or *int64
+	// could be a string or a number, use ExifOrientation to normalize it
 	Orientation interface{} `json:"orientation"`
 
 	// DPI specifies image DPI for two dimensions
@@ -0,0 +1,184 @@
+package file
+
+import (
+	"path"
+	"slices"
+	"time"
+)
+
+// Filter is a composable predicate over file Info. It is used to filter
+// listed files on the client side by the fields the List params do not
+// support.
+//
+// Conditions added to a Filter are combined with AND, use Or and Not for
+// other combinations. Each method returns a new Filter, so filters can be
+// safely reused as a base for others:
+//
+//	images := file.Where().Image(true).SizeBetween(0, 10<<20)
+//	params := file.ListParams{
+//		Filter: images.UploadedBetween(weekAgo, now),
+//	}
+type Filter struct {
+	conds []func(*Info) bool
+
+	// uploaded window narrowing the request on the server side
+	uploadedFrom *time.Time
+	uploadedTo   *time.Time
+}
+
+// Where returns an empty Filter matching every file
+func Where() *Filter { return &Filter{} }
+
+// Match reports whether the file matches all of the filter conditions
+func (f *Filter) Match(info *Info) bool {
+	if f == nil {
+		return true
+	}
+	for _, c := range f.conds {
+		if !c(info) {
+			return false
+		}
+	}
+	return true
+}
+
+// MimeType matches files with one of the MIME types. A pattern may end
+// with a wildcard subtype, e.g. "image/*".
+func (f *Filter) MimeType(patterns ...string) *Filter {
+	return f.and(func(info *Info) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, info.MimeType); ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// SizeBetween matches files of min to max bytes inclusively. Zero max
+// means no upper bound.
+func (f *Filter) SizeBetween(min, max uint64) *Filter {
+	return f.and(func(info *Info) bool {
+		return info.Size >= min && (max == 0 || info.Size <= max)
+	})
+}
+
+// UploadedBetween matches files uploaded within the [from, to) window.
+// Zero from or to means the window is not bounded on that side.
+//
+// When the filter is passed to List, the window also narrows the request
+// with ListParams.StartingFrom and the listing stops as soon as files
+// ordered by upload date leave the window.
+func (f *Filter) UploadedBetween(from, to time.Time) *Filter {
+	nf := f.and(func(info *Info) bool {
+		if info.UploadedAt == nil {
+			return false
+		}
+		at := info.UploadedAt.Time
+		return (from.IsZero() || !at.Before(from)) &&
+			(to.IsZero() || at.Before(to))
+	})
+	if !from.IsZero() && (nf.uploadedFrom == nil || from.After(*nf.uploadedFrom)) {
+		nf.uploadedFrom = &from
+	}
+	if !to.IsZero() && (nf.uploadedTo == nil || to.Before(*nf.uploadedTo)) {
+		nf.uploadedTo = &to
+	}
+	return nf
+}
+
+// Image matches image files if isImage is true and other files otherwise
+func (f *Filter) Image(isImage bool) *Filter {
+	return f.and(func(info *Info) bool { return info.IsImage == isImage })
+}
+
+// Source matches files uploaded from one of the sources, e.g. "facebook"
+func (f *Filter) Source(sources ...string) *Filter {
+	return f.and(func(info *Info) bool {
+		return info.Source != nil && slices.Contains(sources, *info.Source)
+	})
+}
+
+// Metadata matches files having the metadata key set to the value
+func (f *Filter) Metadata(key, value string) *Filter {
+	return f.and(func(info *Info) bool {
+		v, ok := info.Metadata[key]
+		return ok && v == value
+	})
+}
+
+// HasMetadata matches files having the metadata key set to any value
+func (f *Filter) HasMetadata(key string) *Filter {
+	return f.and(func(info *Info) bool {
+		_, ok := info.Metadata[key]
+		return ok
+	})
+}
+
+// Func matches files the custom predicate returns true for
+func (f *Filter) Func(match func(*Info) bool) *Filter {
+	return f.and(match)
+}
+
+// Or matches files matching at least one of the filters
+func Or(filters ...*Filter) *Filter {
+	return Where().and(func(info *Info) bool {
+		for _, of := range filters {
+			if of.Match(info) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not matches files not matching the filter
+func Not(filter *Filter) *Filter {
+	return Where().and(func(info *Info) bool { return !filter.Match(info) })
+}
+
+func (f *Filter) and(cond func(*Info) bool) *Filter {
+	var nf Filter
+	if f != nil {
+		nf = *f
+	}
+	nf.conds = append(slices.Clip(nf.conds), cond)
+	return &nf
+}
+
+// narrow sets the request starting point to the filter upload window
+// boundary and returns the predicate telling when listing can stop.
+func (f *Filter) narrow(params *ListParams) (stop func(*Info) bool) {
+	desc := params.OrderBy != nil && *params.OrderBy == OrderByUploadedAtDesc
+	if params.OrderBy != nil && !desc && *params.OrderBy != OrderByUploadedAtAsc {
+		return nil
+	}
+
+	start, end := f.uploadedFrom, f.uploadedTo
+	if desc {
+		start, end = end, start
+	}
+
+	if start != nil {
+		from := start.UTC()
+		if params.StartingFrom == nil ||
+			(!desc && from.After(*params.StartingFrom)) ||
+			(desc && from.Before(*params.StartingFrom)) {
+			params.StartingFrom = &from
+		}
+	}
+
+	if end == nil {
+		return nil
+	}
+	bound := *end
+	return func(info *Info) bool {
+		if info.UploadedAt == nil {
+			return false
+		}
+		if desc {
+			return info.UploadedAt.Before(bound)
+		}
+		return !info.UploadedAt.Before(bound)
+	}
+}
@@ -0,0 +1,186 @@
+package file
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+var filterDay = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func uploadedAt(hours int) *config.Time {
+	return &config.Time{Time: filterDay.Add(time.Duration(hours) * time.Hour)}
+}
+
+func TestFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	photo := &Info{
+		BasicFileInfo: BasicFileInfo{MimeType: "image/jpeg", Size: 2048, IsImage: true},
+		UploadedAt:    uploadedAt(10),
+		Source:        ucare.String("facebook"),
+		Metadata:      map[string]string{"env": "preview"},
+	}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   bool
+	}{
+		{"nil", nil, true},
+		{"empty", Where(), true},
+		{"mime_wildcard", Where().MimeType("video/*", "image/*"), true},
+		{"mime_mismatch", Where().MimeType("application/pdf"), false},
+		{"size_in_range", Where().SizeBetween(1024, 4096), true},
+		{"size_no_upper_bound", Where().SizeBetween(1024, 0), true},
+		{"size_too_small", Where().SizeBetween(4096, 0), false},
+		{"uploaded_within", Where().UploadedBetween(filterDay, filterDay.Add(24*time.Hour)), true},
+		{"uploaded_window_end_exclusive", Where().UploadedBetween(time.Time{}, filterDay.Add(10*time.Hour)), false},
+		{"image", Where().Image(true), true},
+		{"not_image", Where().Image(false), false},
+		{"source", Where().Source("gdrive", "facebook"), true},
+		{"metadata", Where().Metadata("env", "preview"), true},
+		{"metadata_other_value", Where().Metadata("env", "prod"), false},
+		{"has_metadata", Where().HasMetadata("env"), true},
+		{"func", Where().Func(func(i *Info) bool { return i.Size > 4096 }), false},
+		{"and", Where().Image(true).MimeType("image/png"), false},
+		{"or", Or(Where().MimeType("image/png"), Where().Source("facebook")), true},
+		{"not", Not(Where().Image(true)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.filter.Match(photo))
+		})
+	}
+}
+
+func TestFilter_Reuse(t *testing.T) {
+	t.Parallel()
+
+	base := Where().Image(true)
+	png := base.MimeType("image/png")
+	jpeg := base.MimeType("image/jpeg")
+
+	info := &Info{BasicFileInfo: BasicFileInfo{MimeType: "image/jpeg", IsImage: true}}
+	assert.True(t, base.Match(info))
+	assert.False(t, png.Match(info))
+	assert.True(t, jpeg.Match(info))
+}
+
+func TestList_Filter(t *testing.T) {
+	t.Parallel()
+
+	files := []Info{
+		{BasicFileInfo: BasicFileInfo{ID: "f1", IsImage: true}, UploadedAt: uploadedAt(1)},
+		{BasicFileInfo: BasicFileInfo{ID: "f2"}, UploadedAt: uploadedAt(2)},
+		{BasicFileInfo: BasicFileInfo{ID: "f3", IsImage: true}, UploadedAt: uploadedAt(3)},
+		{BasicFileInfo: BasicFileInfo{ID: "f4", IsImage: true}, UploadedAt: uploadedAt(30)},
+	}
+
+	var requests atomic.Int32
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "2024-05-01T00:00:00", r.URL.Query().Get("from"))
+
+		uctest.RespondJSON(t, w, map[string]any{
+			"next":    "http://" + r.Host + "/files/?page=2",
+			"results": files,
+		})
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(context.Background(), ListParams{
+			Filter: Where().Image(true).UploadedBetween(filterDay, filterDay.Add(24*time.Hour)),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"f1", "f3"}, readIDs(t, list, len(files)))
+		assert.False(t, list.Next())
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestList_FilterCursor(t *testing.T) {
+	t.Parallel()
+
+	skipF2 := Where().Func(func(i *Info) bool { return i.ID != "f2" })
+	uctest.WithHTTPServer(t, pagedFiles(t, pagedIDs, 2), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(context.Background(), ListParams{Filter: skipF2})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"f1"}, readIDs(t, list, 1))
+		require.True(t, list.Next()) // reads f3 ahead
+		cursor := list.Cursor()
+
+		resumed, err := svc.List(context.Background(), ListParams{Cursor: &cursor, Filter: skipF2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"f3", "f4", "f5"}, readIDs(t, resumed, len(pagedIDs)))
+	})
+}
+
+func TestList_FilterPrevPage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("read_ahead", func(t *testing.T) {
+		t.Parallel()
+
+		skipF2 := Where().Func(func(i *Info) bool { return i.ID != "f2" })
+		uctest.WithHTTPServer(t, pagedFiles(t, pagedIDs, 2), func(t *testing.T, srv *httptest.Server) {
+			svc := NewService(uctest.NewServerClient(srv))
+			list, err := svc.List(context.Background(), ListParams{Filter: skipF2})
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"f1"}, readIDs(t, list, 1))
+			require.True(t, list.Next()) // reads f3 ahead from the second page
+
+			require.NoError(t, list.PrevPage())
+			assert.Equal(t, []string{"f1", "f3", "f4", "f5"}, readIDs(t, list, len(pagedIDs)))
+		})
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		t.Parallel()
+
+		pages := map[string][]Info{
+			"1": {
+				{BasicFileInfo: BasicFileInfo{ID: "f1"}, UploadedAt: uploadedAt(1)},
+				{BasicFileInfo: BasicFileInfo{ID: "f2"}, UploadedAt: uploadedAt(2)},
+			},
+			"2": {
+				{BasicFileInfo: BasicFileInfo{ID: "f3"}, UploadedAt: uploadedAt(3)},
+				{BasicFileInfo: BasicFileInfo{ID: "f4"}, UploadedAt: uploadedAt(30)},
+			},
+		}
+		uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			base := "http://" + r.Host + "/files/?page="
+			resp := map[string]any{"next": base + "2", "previous": nil, "results": pages["1"]}
+			if r.URL.Query().Get("page") == "2" {
+				resp = map[string]any{"next": nil, "previous": base + "1", "results": pages["2"]}
+			}
+			uctest.RespondJSON(t, w, resp)
+		}), func(t *testing.T, srv *httptest.Server) {
+			svc := NewService(uctest.NewServerClient(srv))
+			list, err := svc.List(context.Background(), ListParams{
+				Filter: Where().UploadedBetween(filterDay, filterDay.Add(24*time.Hour)),
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"f1", "f2", "f3"}, readIDs(t, list, 4))
+			assert.False(t, list.Next(), "f4 stops the list")
+
+			require.NoError(t, list.PrevPage())
+			assert.Equal(t, []string{"f1", "f2", "f3"}, readIDs(t, list, 4))
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/uploadcare/uploadcare-go/v2/fanout"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// InfoManyParams holds params for the InfoMany function
+type InfoManyParams struct {
+	InfoParams
+
+	// Concurrency limits the number of Info requests running at once.
+	// Defaults to fanout.DefaultConcurrency.
+	Concurrency int
+}
+
+// InfoManyResult holds InfoMany lookup results
+type InfoManyResult struct {
+	// Found holds info of the found files keyed by file ID
+	Found map[string]Info
+
+	// NotFound holds IDs of the files missing in the project in the order
+	// they have been passed
+	NotFound []string
+
+	// Failed holds errors of the failed lookups keyed by file ID
+	Failed map[string]error
+}
+
+type infoLookup struct {
+	id   string
+	info Info
+	err  error
+	done bool
+}
+
+// InfoMany acquires file-specific info for many files at once, running up
+// to params.Concurrency svc.Info requests concurrently. Every unique ID
+// ends up in exactly one of the result fields. The error is only returned
+// when the context is done before all lookups complete, lookups that have
+// not been made are reported as failed with the context error.
+//
+// Example usage:
+//
+//	res, err := file.InfoMany(ctx, fileSvc, ids, nil)
+func InfoMany(
+	ctx context.Context,
+	svc Service,
+	ids []string,
+	params *InfoManyParams,
+) (InfoManyResult, error) {
+	if params == nil {
+		params = &InfoManyParams{}
+	}
+
+	var lookups []infoLookup
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			lookups = append(lookups, infoLookup{id: id})
+		}
+	}
+
+	infoParams := params.InfoParams
+	_ = fanout.Run(
+		ctx,
+		fanout.Slice(lookups),
+		func(ctx context.Context, l *infoLookup) error {
+			l.info, l.err = svc.Info(ctx, l.id, &infoParams)
+			l.done = true
+			return l.err
+		},
+		fanout.Options[infoLookup]{
+			Concurrency:     params.Concurrency,
+			ContinueOnError: true,
+		},
+	)
+
+	res := InfoManyResult{
+		Found:  make(map[string]Info, len(lookups)),
+		Failed: map[string]error{},
+	}
+	for _, l := range lookups {
+		switch {
+		case !l.done:
+			res.Failed[l.id] = ctx.Err()
+		case isNotFound(l.err):
+			res.NotFound = append(res.NotFound, l.id)
+		case l.err != nil:
+			res.Failed[l.id] = l.err
+		default:
+			res.Found[l.id] = l.info
+		}
+	}
+
+	return res, ctx.Err()
+}
+
+func isNotFound(err error) bool {
+	var apiErr ucare.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,62 @@
+package file
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+func TestInfoMany(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "appdata", r.URL.Query().Get("include"))
+
+		id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/files/"), "/")
+		switch id {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"detail":"Not found."}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			uctest.RespondJSON(t, w, Info{BasicFileInfo: BasicFileInfo{ID: id}})
+		}
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		res, err := InfoMany(
+			context.Background(),
+			svc,
+			[]string{"a", "missing", "b", "broken", "a"},
+			&InfoManyParams{InfoParams: InfoParams{Include: ucare.String("appdata")}},
+		)
+		require.NoError(t, err)
+
+		assert.Len(t, res.Found, 2)
+		assert.Equal(t, "a", res.Found["a"].ID)
+		assert.Equal(t, "b", res.Found["b"].ID)
+		assert.Equal(t, []string{"missing"}, res.NotFound)
+		require.Len(t, res.Failed, 1)
+		assert.Error(t, res.Failed["broken"])
+	})
+}
+
+func TestInfoMany_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	svc := NewService(&uctest.Client{})
+	res, err := InfoMany(ctx, svc, []string{"a", "b"}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, res.Failed["a"], context.Canceled)
+	assert.ErrorIs(t, res.Failed["b"], context.Canceled)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/uploadcare/uploadcare-go/v2/internal/codec"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
 )
 
 // ListParams holds all possible params for for the List method
@@ -35,6 +36,17 @@ type ListParams struct {
 	// Include specifies additional fields to include in the response.
 	// Valid value: "appdata".
 	Include *string `form:"include"`
+
+	// Cursor resumes the list from a token previously returned by
+	// List.Cursor. When set, the rest of the params are ignored as the
+	// cursor already holds the query the list has been started with.
+	Cursor *string
+
+	// Filter skips listed files not matching it. Unlike the rest of the
+	// params it is applied on the client side, except for the upload date
+	// window which also narrows the request, see Filter.UploadedBetween.
+	// Pass the same filter along with Cursor when resuming a list.
+	Filter *Filter
 }
 
 // EncodeReq implements ucare.ReqEncoder
@@ -44,16 +56,108 @@ func (d *ListParams) EncodeReq(req *http.Request) error {
 
 // List holds a list of files
 type List struct {
-	raw     codec.NextRawResulter
+	raw     codec.Pager
 	cdnBase string
+
+	filter *Filter
+	stop   func(*Info) bool // tells when no more files match the filter
+
+	// the next file matching the filter read ahead by Next
+	pending       *Info
+	pendingErr    error
+	pendingCursor string
+	done          bool
 }
 
 // Next indicates if there is a result to read
-func (v *List) Next() bool { return v.raw.Next() }
+func (v *List) Next() bool {
+	if v.filter == nil {
+		return v.raw.Next()
+	}
+	if v.pending != nil || v.pendingErr != nil {
+		return true
+	}
+	for !v.done && v.raw.Next() {
+		cursor := v.raw.Cursor()
+		fi, err := v.readResult()
+		if err != nil {
+			v.pendingErr, v.pendingCursor = err, cursor
+			return true
+		}
+		if v.stop != nil && v.stop(fi) {
+			v.done = true
+			break
+		}
+		if v.filter.Match(fi) {
+			v.pending, v.pendingCursor = fi, cursor
+			return true
+		}
+	}
+	return false
+}
+
+// Total returns the total number of files in the list across all pages.
+// ListParams.Filter is not taken into account.
+func (v *List) Total() uint64 { return v.raw.PageInfo().Total }
+
+// PerPage returns the number of files returned per single page
+func (v *List) PerPage() uint64 { return v.raw.PageInfo().PerPage }
 
-// ReadResult returns next Info value. If no results are left to read it
-// returns ucare.ErrEndOfResults.
+// PageInfo returns details of the page currently being read, including
+// the links to the next and previous pages.
+func (v *List) PageInfo() ucare.PageInfo { return v.raw.PageInfo() }
+
+// Cursor returns a token pointing at the next result to read. Pass it as
+// ListParams.Cursor to a new List call to resume reading from that point,
+// e.g. after a process restart.
+func (v *List) Cursor() string {
+	if v.pending != nil || v.pendingErr != nil {
+		return v.pendingCursor
+	}
+	return v.raw.Cursor()
+}
+
+// Prefetch makes the list request up to depth upcoming pages in the
+// background while the current page is being read, so ReadResult does not
+// stall on page boundaries. Prefetched pages are requested through the same
+// client, honouring its retry settings, and prefetching stops once the
+// context passed to List is done. Zero depth turns prefetching off.
+//
+// Call Close if the list is abandoned before all files are read and the
+// context is never canceled.
+func (v *List) Prefetch(depth int) { v.raw.Prefetch(depth) }
+
+// Close stops prefetching pages in the background
+func (v *List) Close() { v.raw.Close() }
+
+// PrevPage navigates the list one page back. Subsequent ReadResult calls
+// read the previous page from its beginning. It returns
+// ucare.ErrNoPrevPage if the current page is the first one.
+func (v *List) PrevPage() error {
+	if err := v.raw.ReadPrevPage(); err != nil {
+		return err
+	}
+	// the filter state belongs to the pages read so far
+	v.pending, v.pendingErr, v.pendingCursor = nil, nil, ""
+	v.done = false
+	return nil
+}
+
+// ReadResult returns next Info value matching the ListParams.Filter, if any.
+// If no results are left to read it returns ucare.ErrEndOfResults.
 func (v *List) ReadResult() (*Info, error) {
+	if v.filter == nil {
+		return v.readResult()
+	}
+	if !v.Next() {
+		return nil, codec.ErrEndOfResults
+	}
+	fi, err := v.pending, v.pendingErr
+	v.pending, v.pendingErr = nil, nil
+	return fi, err
+}
+
+func (v *List) readResult() (*Info, error) {
 	raw, err := v.raw.ReadRawResult()
 	if err != nil {
 		return nil, err
@@ -84,6 +188,16 @@ func (v *List) ReadResult() (*Info, error) {
 //		...
 //	}
 func (s service) List(ctx context.Context, params ListParams) (*List, error) {
-	resbuf, err := s.svc.List(ctx, listPathFormat, &params)
-	return &List{raw: resbuf, cdnBase: s.cdnBase}, err
+	list := List{cdnBase: s.cdnBase, filter: params.Filter}
+	if params.Filter != nil {
+		list.stop = params.Filter.narrow(&params)
+	}
+
+	var err error
+	if params.Cursor != nil {
+		list.raw, err = s.svc.ListFrom(ctx, listPathFormat, *params.Cursor)
+	} else {
+		list.raw, err = s.svc.List(ctx, listPathFormat, &params)
+	}
+	return &list, err
 }
@@ -0,0 +1,253 @@
+package file
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+var pagedIDs = []string{"f1", "f2", "f3", "f4", "f5"}
+
+// pagedFiles serves ids split into pages of perPage files, linking them
+// through absolute next/previous URLs like the REST API does.
+func pagedFiles(t *testing.T, ids []string, perPage int) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files/", r.URL.Path)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+perPage, len(ids))
+
+		results := make([]Info, 0, end-offset)
+		for _, id := range ids[offset:end] {
+			results = append(results, Info{BasicFileInfo: BasicFileInfo{ID: id}})
+		}
+
+		resp := map[string]any{
+			"next":     nil,
+			"previous": nil,
+			"total":    len(ids),
+			"per_page": perPage,
+			"results":  results,
+		}
+		base := "http://" + r.Host + "/files/?offset="
+		if end < len(ids) {
+			resp["next"] = fmt.Sprintf("%s%d", base, end)
+		}
+		if offset > 0 {
+			resp["previous"] = fmt.Sprintf("%s%d", base, max(offset-perPage, 0))
+		}
+		uctest.RespondJSON(t, w, resp)
+	})
+}
+
+func readIDs(t *testing.T, list *List, n int) []string {
+	t.Helper()
+	ids := []string{}
+	for len(ids) < n && list.Next() {
+		info, err := list.ReadResult()
+		require.NoError(t, err)
+		ids = append(ids, info.ID)
+	}
+	return ids
+}
+
+func TestList_PageInfo(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, pagedFiles(t, pagedIDs, 2), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(context.Background(), ListParams{})
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(5), list.Total())
+		assert.Equal(t, uint64(2), list.PerPage())
+		assert.Equal(t, srv.URL+"/files/", list.PageInfo().Current)
+		assert.Nil(t, list.PageInfo().Previous)
+
+		assert.Equal(t, pagedIDs, readIDs(t, list, len(pagedIDs)))
+
+		page := list.PageInfo()
+		assert.Equal(t, srv.URL+"/files/?offset=4", page.Current)
+		assert.Nil(t, page.Next)
+		require.NotNil(t, page.Previous)
+		assert.Equal(t, srv.URL+"/files/?offset=2", *page.Previous)
+	})
+}
+
+func TestList_PrevPage(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, pagedFiles(t, pagedIDs, 2), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(context.Background(), ListParams{})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, list.PrevPage(), ucare.ErrNoPrevPage)
+
+		assert.Equal(t, []string{"f1", "f2", "f3"}, readIDs(t, list, 3))
+
+		require.NoError(t, list.PrevPage())
+		assert.Equal(t, pagedIDs, readIDs(t, list, len(pagedIDs)))
+	})
+}
+
+func TestList_Cursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		read int
+	}{
+		{"mid_page", 3},
+		{"page_boundary", 2},
+		{"start", 0},
+		{"exhausted", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			uctest.WithHTTPServer(t, pagedFiles(t, pagedIDs, 2), func(t *testing.T, srv *httptest.Server) {
+				svc := NewService(uctest.NewServerClient(srv))
+				list, err := svc.List(context.Background(), ListParams{})
+				require.NoError(t, err)
+				require.Len(t, readIDs(t, list, tt.read), tt.read)
+
+				cursor := list.Cursor()
+
+				resumed, err := svc.List(context.Background(), ListParams{Cursor: &cursor})
+				require.NoError(t, err)
+				assert.Equal(t, pagedIDs[tt.read:], readIDs(t, resumed, len(pagedIDs)))
+			})
+		})
+	}
+}
+
+func TestList_InvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not_base64", "!!!"},
+		{"not_json", "bm90LWpzb24"},
+		{"foreign_resource", base64.RawURLEncoding.EncodeToString(
+			[]byte(`{"p":"https://api.uploadcare.com/groups/"}`),
+		)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := NewService(&uctest.Client{})
+			_, err := svc.List(context.Background(), ListParams{Cursor: &tt.cursor})
+			assert.ErrorIs(t, err, ucare.ErrInvalidCursor)
+		})
+	}
+}
+
+func TestList_Prefetch(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	handler := pagedFiles(t, pagedIDs, 2)
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler.ServeHTTP(w, r)
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(context.Background(), ListParams{})
+		require.NoError(t, err)
+		defer list.Close()
+
+		list.Prefetch(2)
+		assert.Eventually(t, func() bool { return requests.Load() == 3 }, time.Second, time.Millisecond)
+
+		assert.Equal(t, pagedIDs, readIDs(t, list, len(pagedIDs)))
+		assert.Equal(t, int32(3), requests.Load())
+		assert.Equal(t, srv.URL+"/files/?offset=4", list.PageInfo().Current)
+	})
+}
+
+func TestList_PrefetchWaitUnlocked(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	handler := pagedFiles(t, pagedIDs, 2)
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "2" {
+			<-release
+		}
+		handler.ServeHTTP(w, r)
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(context.Background(), ListParams{})
+		require.NoError(t, err)
+		defer list.Close()
+		defer func() {
+			select {
+			case <-release:
+			default:
+				close(release)
+			}
+		}()
+
+		list.Prefetch(1)
+		assert.Equal(t, []string{"f1", "f2"}, readIDs(t, list, 2))
+
+		read := make(chan string, 1)
+		go func() {
+			info, err := list.ReadResult()
+			assert.NoError(t, err)
+			read <- info.ID
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		// the reader waits for the prefetched page without holding the list
+		done := make(chan struct{})
+		go func() {
+			_ = list.PageInfo()
+			_ = list.Cursor()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("list is locked while waiting for a prefetched page")
+		}
+
+		close(release)
+		assert.Equal(t, "f3", <-read)
+	})
+}
+
+func TestList_PrefetchCanceled(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, pagedFiles(t, pagedIDs, 2), func(t *testing.T, srv *httptest.Server) {
+		ctx, cancel := context.WithCancel(context.Background())
+		svc := NewService(uctest.NewServerClient(srv))
+		list, err := svc.List(ctx, ListParams{})
+		require.NoError(t, err)
+
+		list.Prefetch(1)
+		cancel()
+
+		assert.Equal(t, []string{"f1", "f2"}, readIDs(t, list, 2))
+		_, err = list.ReadResult()
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
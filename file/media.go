@@ -0,0 +1,157 @@
+package file
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Orientation is an image orientation from EXIF telling how the stored
+// image must be transformed to be displayed upright
+type Orientation int
+
+// EXIF orientation values
+const (
+	OrientationUnknown Orientation = iota
+	OrientationNormal
+	OrientationMirrorHorizontal
+	OrientationRotate180
+	OrientationMirrorVertical
+	OrientationMirrorHorizontalRotate270
+	OrientationRotate90
+	OrientationMirrorHorizontalRotate90
+	OrientationRotate270
+)
+
+// SwapsDimensions reports whether the image width and height are swapped
+// when the image is displayed
+func (o Orientation) SwapsDimensions() bool {
+	return o >= OrientationMirrorHorizontalRotate270 && o <= OrientationRotate270
+}
+
+// ExifOrientation returns the normalized image orientation. It returns
+// OrientationUnknown if the orientation is absent or malformed.
+func (v *ImageInfo) ExifOrientation() Orientation {
+	if v == nil {
+		return OrientationUnknown
+	}
+
+	var n int64
+	switch o := v.Orientation.(type) {
+	case float64:
+		n = int64(o)
+	case int:
+		n = int64(o)
+	case int64:
+		n = o
+	case *int64:
+		if o == nil {
+			return OrientationUnknown
+		}
+		n = *o
+	case json.Number:
+		n, _ = o.Int64()
+	case string:
+		n, _ = strconv.ParseInt(strings.TrimSpace(o), 10, 64)
+	case *string:
+		if o == nil {
+			return OrientationUnknown
+		}
+		n, _ = strconv.ParseInt(strings.TrimSpace(*o), 10, 64)
+	}
+
+	if n < int64(OrientationNormal) || n > int64(OrientationRotate270) {
+		return OrientationUnknown
+	}
+	return Orientation(n)
+}
+
+// DisplayWidth returns the image width after the EXIF orientation is applied
+func (v *ImageInfo) DisplayWidth() uint64 {
+	if v == nil {
+		return 0
+	}
+	if v.ExifOrientation().SwapsDimensions() {
+		return v.Height
+	}
+	return v.Width
+}
+
+// DisplayHeight returns the image height after the EXIF orientation is
+// applied
+func (v *ImageInfo) DisplayHeight() uint64 {
+	if v == nil {
+		return 0
+	}
+	if v.ExifOrientation().SwapsDimensions() {
+		return v.Width
+	}
+	return v.Height
+}
+
+// AspectRatio returns the displayed image width to height ratio, or zero
+// if the dimensions are unknown
+func (v *ImageInfo) AspectRatio() float64 {
+	w, h := v.DisplayWidth(), v.DisplayHeight()
+	if w == 0 || h == 0 {
+		return 0
+	}
+	return float64(w) / float64(h)
+}
+
+// Resolution returns the horizontal and vertical image DPI
+func (v *ImageInfo) Resolution() (x, y float64, ok bool) {
+	if v == nil || len(v.DPI) == 0 {
+		return 0, 0, false
+	}
+	x, y = v.DPI[0], v.DPI[0]
+	if len(v.DPI) > 1 {
+		y = v.DPI[1]
+	}
+	return x, y, true
+}
+
+// datetimeOriginalLayouts are formats DateTimeOriginal comes in
+var datetimeOriginalLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006:01:02 15:04:05",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// TakenAt returns the parsed EXIF DateTimeOriginal. The time has no zone
+// information in EXIF, so it is returned in UTC.
+func (v *ImageInfo) TakenAt() (time.Time, bool) {
+	if v == nil || v.DateTimeOriginal == nil {
+		return time.Time{}, false
+	}
+	s := strings.TrimSpace(*v.DateTimeOriginal)
+	for _, layout := range datetimeOriginalLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Coordinates returns the EXIF geo-location if it is present and valid
+func (v *ImageInfo) Coordinates() (Location, bool) {
+	if v == nil || v.GeoLocation == nil {
+		return Location{}, false
+	}
+	l := *v.GeoLocation
+	if math.Abs(l.Latitude) > 90 || math.Abs(l.Longitude) > 180 {
+		return Location{}, false
+	}
+	return l, true
+}
+
+// Duration returns the video duration
+func (c *ContentInfo) Duration() (time.Duration, bool) {
+	if c == nil || c.Video == nil || c.Video.Duration == nil {
+		return 0, false
+	}
+	return time.Duration(*c.Video.Duration) * time.Millisecond, true
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+func TestImageInfo_ExifOrientation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		json string
+		want Orientation
+	}{
+		{"number", `6`, OrientationRotate90},
+		{"string", `"8"`, OrientationRotate270},
+		{"null", `null`, OrientationUnknown},
+		{"out_of_range", `9`, OrientationUnknown},
+		{"garbage", `"upright"`, OrientationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var img ImageInfo
+			require.NoError(t, json.Unmarshal([]byte(`{"orientation": `+tt.json+`}`), &img))
+			assert.Equal(t, tt.want, img.ExifOrientation())
+		})
+	}
+}
+
+func TestImageInfo_Display(t *testing.T) {
+	t.Parallel()
+
+	rotated := &ImageInfo{Width: 4000, Height: 3000, Orientation: float64(OrientationRotate90)}
+	assert.Equal(t, uint64(3000), rotated.DisplayWidth())
+	assert.Equal(t, uint64(4000), rotated.DisplayHeight())
+	assert.Equal(t, 0.75, rotated.AspectRatio())
+
+	upright := &ImageInfo{Width: 4000, Height: 3000, Orientation: "1"}
+	assert.Equal(t, uint64(4000), upright.DisplayWidth())
+	assert.InDelta(t, 1.333, upright.AspectRatio(), 0.001)
+
+	var missing *ImageInfo
+	assert.Equal(t, uint64(0), missing.DisplayWidth())
+	assert.Zero(t, missing.AspectRatio())
+}
+
+func TestImageInfo_Exif(t *testing.T) {
+	t.Parallel()
+
+	img := &ImageInfo{
+		DPI:              []float64{72, 96},
+		DateTimeOriginal: ucare.String("2018:09:13 16:23:40"),
+		GeoLocation:      &Location{Latitude: 55.62, Longitude: 37.68},
+	}
+
+	x, y, ok := img.Resolution()
+	assert.True(t, ok)
+	assert.Equal(t, []float64{72, 96}, []float64{x, y})
+
+	taken, ok := img.TakenAt()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2018, 9, 13, 16, 23, 40, 0, time.UTC), taken)
+
+	loc, ok := img.Coordinates()
+	assert.True(t, ok)
+	assert.Equal(t, 55.62, loc.Latitude)
+
+	img.GeoLocation = &Location{Latitude: 120}
+	_, ok = img.Coordinates()
+	assert.False(t, ok)
+}
+
+func TestContentInfo_Duration(t *testing.T) {
+	t.Parallel()
+
+	d, ok := (&ContentInfo{Video: &VideoInfo{Duration: ucare.Uint64(90500)}}).Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 90500*time.Millisecond, d)
+
+	var none *ContentInfo
+	_, ok = none.Duration()
+	assert.False(t, ok)
+}
@@ -0,0 +1,194 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// OpenParams holds params for the Open function
+type OpenParams struct {
+	// Transformations is a CDN transformation chain applied to the file,
+	// e.g. "-/resize/800x/-/format/webp/". The leading "-/" and the
+	// trailing slash are optional.
+	Transformations string
+
+	// Offset is the number of bytes to skip from the content start
+	Offset int64
+
+	// Length limits the number of bytes to read. Zero means reading till
+	// the end of the content.
+	Length int64
+}
+
+// Content is the file content opened by Open. It must be closed after
+// reading.
+type Content struct {
+	io.ReadCloser
+
+	// Size is the number of bytes to read, -1 if unknown
+	Size int64
+
+	// ContentType is the MIME type of the content
+	ContentType string
+}
+
+// ErrNoCDNURL is returned by Open when the file CDN URL can not be built,
+// e.g. for removed files
+var ErrNoCDNURL = errors.New("file has no CDN URL")
+
+// Open reads the file content from the CDN, optionally transformed.
+// The content URL is built against the client CDN base, see
+// ucare.ClientCDNBase, or the file original URL when the client has none.
+// The request is made through the client's HTTP client and retried when
+// throttled according to the client retry settings, see
+// ucare.ClientDownload. Offset and Length are requested with the Range
+// header, io.EOF is returned if Offset is past the content end.
+//
+// Example usage:
+//
+//	content, err := file.Open(ctx, client, id, &file.OpenParams{
+//		Transformations: "-/preview/200x200/",
+//	})
+//	if err != nil {
+//		// handle error
+//	}
+//	defer content.Close()
+//	_, err = io.Copy(w, content)
+func Open(
+	ctx context.Context,
+	client ucare.Client,
+	id string,
+	params *OpenParams,
+) (*Content, error) {
+	if params == nil {
+		params = &OpenParams{}
+	}
+	if params.Offset < 0 || params.Length < 0 {
+		return nil, errors.New("negative content range")
+	}
+
+	rawURL, err := contentURL(ctx, client, id, params.Transformations)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	ranged := params.Offset > 0 || params.Length > 0
+	if ranged {
+		end := ""
+		if params.Length > 0 {
+			end = fmt.Sprint(params.Offset + params.Length - 1)
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%s", params.Offset, end))
+	}
+
+	log.Debugf("opening file content: %s", rawURL)
+
+	resp, err := ucare.ClientDownload(client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+	case resp.StatusCode == http.StatusOK:
+		if ranged {
+			// the range is ignored, cut it out on our own
+			return cutRange(resp, params.Offset, params.Length)
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		_ = resp.Body.Close()
+		return nil, io.EOF
+	default:
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		detail := strings.TrimSpace(string(body))
+		if detail == "" {
+			detail = resp.Status
+		}
+		return nil, ucare.APIError{StatusCode: resp.StatusCode, Detail: detail}
+	}
+
+	return &Content{
+		ReadCloser:  resp.Body,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+func cutRange(resp *http.Response, offset, length int64) (*Content, error) {
+	if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+		_ = resp.Body.Close()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	size := int64(-1)
+	if resp.ContentLength >= 0 {
+		size = resp.ContentLength - offset
+		if length > 0 {
+			size = min(size, length)
+		}
+	} else if length > 0 {
+		size = length
+	}
+
+	var r io.Reader = resp.Body
+	if length > 0 {
+		r = io.LimitReader(r, length)
+	}
+	return &Content{
+		ReadCloser:  readCloser{r, resp.Body},
+		Size:        size,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// contentURL builds the file CDN URL with the transformations applied.
+// Without the client CDN base, the one of the file original URL is used.
+func contentURL(
+	ctx context.Context,
+	client ucare.Client,
+	id, transformations string,
+) (string, error) {
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		info, err := NewService(client).Info(ctx, id, nil)
+		if err != nil {
+			return "", err
+		}
+		if info.OriginalFileURL == nil {
+			return "", ErrNoCDNURL
+		}
+		u, err := url.Parse(*info.OriginalFileURL)
+		if err != nil || u.Host == "" {
+			return "", ErrNoCDNURL
+		}
+		base = u.Scheme + "://" + u.Host
+	}
+
+	rawURL := strings.TrimRight(base, "/") + "/" + url.PathEscape(id) + "/"
+	if t := strings.Trim(transformations, "/"); t != "" {
+		if !strings.HasPrefix(t, "-/") {
+			t = "-/" + t
+		}
+		rawURL += t + "/"
+	}
+	return rawURL, nil
+}
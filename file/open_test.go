@@ -0,0 +1,144 @@
+package file
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+const openContent = "0123456789"
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		params      *OpenParams
+		ignoreRange bool
+		wantPath    string
+		wantRange   string
+		want        string
+		wantSize    int64
+	}{{
+		name:     "whole",
+		wantPath: "/abc/",
+		want:     openContent,
+		wantSize: 10,
+	}, {
+		name:     "transformations",
+		params:   &OpenParams{Transformations: "resize/200x/-/format/webp"},
+		wantPath: "/abc/-/resize/200x/-/format/webp/",
+		want:     openContent,
+		wantSize: 10,
+	}, {
+		name:      "range",
+		params:    &OpenParams{Offset: 2, Length: 3},
+		wantPath:  "/abc/",
+		wantRange: "bytes=2-4",
+		want:      "234",
+		wantSize:  3,
+	}, {
+		name:      "open_range",
+		params:    &OpenParams{Offset: 7},
+		wantPath:  "/abc/",
+		wantRange: "bytes=7-",
+		want:      "789",
+		wantSize:  3,
+	}, {
+		name:        "range_ignored",
+		params:      &OpenParams{Offset: 2, Length: 3},
+		ignoreRange: true,
+		wantPath:    "/abc/",
+		wantRange:   "bytes=2-4",
+		want:        "234",
+		wantSize:    3,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.wantPath, r.URL.Path)
+				assert.Equal(t, tc.wantRange, r.Header.Get("Range"))
+				w.Header().Set("Content-Type", "image/webp")
+				content := strings.NewReader(openContent)
+				if tc.ignoreRange {
+					_, _ = io.Copy(w, content)
+					return
+				}
+				http.ServeContent(w, r, "", time.Time{}, content)
+			}), func(t *testing.T, srv *httptest.Server) {
+				client := uctest.NewServerClient(srv)
+				client.CDN = srv.URL
+
+				content, err := Open(context.Background(), client, "abc", tc.params)
+				require.NoError(t, err)
+				defer content.Close()
+
+				data, err := io.ReadAll(content)
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, string(data))
+				assert.Equal(t, tc.wantSize, content.Size)
+				assert.Equal(t, "image/webp", content.ContentType)
+			})
+		})
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing/" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(openContent))
+	}), func(t *testing.T, srv *httptest.Server) {
+		client := uctest.NewServerClient(srv)
+		client.CDN = srv.URL
+
+		_, err := Open(context.Background(), client, "missing", nil)
+		var apiErr ucare.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "Not found", apiErr.Detail)
+
+		_, err = Open(context.Background(), client, "abc", &OpenParams{Offset: 20})
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestOpen_OriginalFileURLBase(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/files/abc/" {
+			uctest.RespondJSON(t, w, map[string]any{
+				"uuid":              "abc",
+				"original_file_url": "http://" + r.Host + "/abc/photo.jpg",
+			})
+			return
+		}
+		assert.Equal(t, "/abc/-/preview/", r.URL.Path)
+		_, _ = w.Write([]byte(openContent))
+	}), func(t *testing.T, srv *httptest.Server) {
+		content, err := Open(context.Background(), uctest.NewServerClient(srv), "abc", &OpenParams{Transformations: "-/preview/"})
+		require.NoError(t, err)
+		defer content.Close()
+
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, openContent, string(data))
+	})
+}
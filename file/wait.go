@@ -0,0 +1,138 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/addon"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// Default WaitReady polling intervals
+const (
+	DefaultWaitInterval    = 500 * time.Millisecond
+	DefaultWaitMaxInterval = 10 * time.Second
+)
+
+// WaitReadyParams holds params for the WaitReady function
+type WaitReadyParams struct {
+	// ContentInfo makes WaitReady also wait for Info.ContentInfo to be
+	// present
+	ContentInfo bool
+
+	// AppData makes WaitReady also wait for the results of the add-ons to
+	// be present in Info.AppData. Info is requested with include=appdata
+	// when set.
+	AppData []addon.Name
+
+	// Interval is the delay before the second Info request. It doubles
+	// with every next request up to MaxInterval.
+	// Defaults to DefaultWaitInterval.
+	Interval time.Duration
+
+	// MaxInterval caps the delay between Info requests.
+	// Defaults to DefaultWaitMaxInterval.
+	MaxInterval time.Duration
+
+	// Timeout limits the total waiting time. When zero, WaitReady waits
+	// until the context is done.
+	Timeout time.Duration
+}
+
+// WaitTimeoutError is returned by WaitReady when the file does not become
+// ready before the timeout or the context is done
+type WaitTimeoutError struct {
+	// Last is the last file info received
+	Last Info
+	// Err is the context error
+	Err error
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("file %s is not ready: %s", e.Last.ID, e.Err)
+}
+
+func (e *WaitTimeoutError) Unwrap() error { return e.Err }
+
+// WaitReady polls file info with svc.Info and exponential backoff until the
+// file is ready to be used after upload and, optionally, until its content
+// info and add-on results are present. It returns the final Info. If
+// waiting times out, it returns *WaitTimeoutError holding the last Info
+// received. Info request errors are returned as is.
+//
+// Example usage:
+//
+//	info, err := file.WaitReady(ctx, fileSvc, fileID, &file.WaitReadyParams{
+//		AppData: []addon.Name{addon.AddonClamAV},
+//		Timeout: time.Minute,
+//	})
+func WaitReady(
+	ctx context.Context,
+	svc Service,
+	fileID string,
+	params *WaitReadyParams,
+) (Info, error) {
+	if params == nil {
+		params = &WaitReadyParams{}
+	}
+	interval := params.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+	maxInterval := params.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxInterval
+	}
+
+	if params.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.Timeout)
+		defer cancel()
+	}
+
+	var infoParams *InfoParams
+	if len(params.AppData) > 0 {
+		infoParams = &InfoParams{Include: ucare.String("appdata")}
+	}
+
+	var last Info
+	for {
+		info, err := svc.Info(ctx, fileID, infoParams)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, &WaitTimeoutError{Last: last, Err: ctx.Err()}
+			}
+			return info, err
+		}
+		last = info
+
+		if params.isReady(&info) {
+			return info, nil
+		}
+
+		log.Debugf("file %s is not ready, next poll in %s", fileID, interval)
+
+		select {
+		case <-ctx.Done():
+			return last, &WaitTimeoutError{Last: last, Err: ctx.Err()}
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+func (p *WaitReadyParams) isReady(info *Info) bool {
+	if !info.IsReady {
+		return false
+	}
+	if p.ContentInfo && info.ContentInfo == nil {
+		return false
+	}
+	for _, name := range p.AppData {
+		if _, ok := info.AppData[string(name)]; !ok {
+			return false
+		}
+	}
+	return true
+}
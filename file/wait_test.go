@@ -0,0 +1,68 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/addon"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+func TestWaitReady(t *testing.T) {
+	t.Parallel()
+
+	var polls atomic.Int32
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "appdata", r.URL.Query().Get("include"))
+
+		info := Info{BasicFileInfo: BasicFileInfo{ID: testFileUUID}}
+		switch n := polls.Add(1); {
+		case n >= 3:
+			info.AppData = map[string]json.RawMessage{string(addon.AddonClamAV): json.RawMessage(`{}`)}
+			fallthrough
+		case n == 2:
+			info.IsReady = true
+		}
+		uctest.RespondJSON(t, w, info)
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		info, err := WaitReady(context.Background(), svc, testFileUUID, &WaitReadyParams{
+			AppData:  []addon.Name{addon.AddonClamAV},
+			Interval: time.Millisecond,
+		})
+		require.NoError(t, err)
+		assert.True(t, info.IsReady)
+		assert.Contains(t, info.AppData, string(addon.AddonClamAV))
+		assert.Equal(t, int32(3), polls.Load())
+	})
+}
+
+func TestWaitReady_Timeout(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uctest.RespondJSON(t, w, Info{BasicFileInfo: BasicFileInfo{ID: testFileUUID, IsReady: true}})
+	}), func(t *testing.T, srv *httptest.Server) {
+		svc := NewService(uctest.NewServerClient(srv))
+		_, err := WaitReady(context.Background(), svc, testFileUUID, &WaitReadyParams{
+			ContentInfo: true,
+			Interval:    time.Millisecond,
+			MaxInterval: 5 * time.Millisecond,
+			Timeout:     30 * time.Millisecond,
+		})
+
+		var timeoutErr *WaitTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, testFileUUID, timeoutErr.Last.ID)
+		assert.True(t, timeoutErr.Last.IsReady)
+	})
+}
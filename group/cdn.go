@@ -0,0 +1,134 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/uploadcare/uploadcare-go/v2/cdn"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// ArchiveFormat is a format of the group archive served by the CDN
+type ArchiveFormat string
+
+// Archive formats
+const (
+	ArchiveZip ArchiveFormat = "zip"
+	ArchiveTar ArchiveFormat = "tar"
+)
+
+// Group CDN errors
+var (
+	// ErrNoCDNURL is returned when the group CDN URL can not be built
+	ErrNoCDNURL = errors.New("group has no CDN URL")
+
+	// ErrInvalidArchiveFormat is returned by Archive for unknown formats
+	ErrInvalidArchiveFormat = errors.New("invalid group archive format")
+)
+
+// FileURL returns the CDN URL of the group file at the index, counting from
+// zero, with the image operations applied. The URL is built against the
+// group CDNLink. The index is validated against FileCount and
+// cdn.ErrInvalidNth is returned when it is out of range.
+//
+// Example usage:
+//
+//	rawURL, err := info.FileURL(0, cdn.PreviewOp{Width: 200, Height: 200})
+func (v Info) FileURL(i int, ops ...cdn.Op) (string, error) {
+	if i < 0 || uint64(i) >= v.FileCount {
+		return "", fmt.Errorf("%w: %d is out of 0..%d", cdn.ErrInvalidNth, i, int64(v.FileCount)-1)
+	}
+	base, err := cdnLinkBase(v.CDNLink, v.ID)
+	if err != nil {
+		return "", err
+	}
+	b := cdn.NewGroupFile(v.ID, i)
+	for _, op := range ops {
+		b = b.Op(op)
+	}
+	return b.URLWithBase(base)
+}
+
+// cdnLinkBase returns the CDN base of the group CDN link, i.e. the link
+// without the trailing group ID. The base keeps the path prefix the link
+// may have been rewritten with, see ucare.RewriteCDNURL.
+func cdnLinkBase(cdnLink, id string) (string, error) {
+	u, err := url.Parse(cdnLink)
+	if err != nil || u.Host == "" || id == "" {
+		return "", ErrNoCDNURL
+	}
+	prefix, ok := strings.CutSuffix(strings.TrimSuffix(u.Path, "/"), "/"+id)
+	if !ok {
+		return "", ErrNoCDNURL
+	}
+	return u.Scheme + "://" + u.Host + prefix, nil
+}
+
+// Archive streams the archive of all group files from the CDN. The archive
+// URL is built against the client CDN base, see ucare.ClientCDNBase, or the
+// group CDNLink when the client has none. The request is made through the
+// client's HTTP client and retried when throttled according to the client
+// retry settings, see ucare.ClientDownload. The caller must close the
+// returned reader.
+//
+// Example usage:
+//
+//	archive, err := group.Archive(ctx, client, groupID, group.ArchiveZip)
+//	if err != nil {
+//		// handle error
+//	}
+//	defer archive.Close()
+//	_, err = io.Copy(w, archive)
+func Archive(
+	ctx context.Context,
+	client ucare.Client,
+	id string,
+	format ArchiveFormat,
+) (io.ReadCloser, error) {
+	if format != ArchiveZip && format != ArchiveTar {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArchiveFormat, format)
+	}
+
+	base := ucare.ClientCDNBase(client)
+	if base == "" {
+		info, err := NewService(client).Info(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if base, err = cdnLinkBase(info.CDNLink, info.ID); err != nil {
+			return nil, err
+		}
+	}
+	rawURL := fmt.Sprintf(
+		"%s/%s/archive/%s/",
+		strings.TrimRight(base, "/"),
+		url.PathEscape(id),
+		format,
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("downloading group archive: %s", rawURL)
+
+	resp, err := ucare.ClientDownload(client, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		detail := strings.TrimSpace(string(body))
+		if detail == "" {
+			detail = resp.Status
+		}
+		return nil, ucare.APIError{StatusCode: resp.StatusCode, Detail: detail}
+	}
+	return resp.Body, nil
+}
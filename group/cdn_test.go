@@ -0,0 +1,158 @@
+package group
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/cdn"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+func TestInfoFileURL(t *testing.T) {
+	t.Parallel()
+
+	info := Info{ID: rewriteGroupID, FileCount: 3, CDNLink: rewriteCDN + "/" + rewriteGroupID + "/"}
+
+	tests := []struct {
+		name string
+		info Info
+		i    int
+		ops  []cdn.Op
+		want string
+		err  error
+	}{
+		{
+			name: "first",
+			info: info,
+			want: rewriteCDN + "/" + rewriteGroupID + "/nth/0/",
+		},
+		{
+			name: "with_ops",
+			info: info,
+			i:    2,
+			ops:  []cdn.Op{cdn.PreviewOp{Width: 200, Height: 100}, cdn.FormatOp{Format: cdn.FormatWebP}},
+			want: rewriteCDN + "/" + rewriteGroupID + "/nth/2/-/preview/200x100/-/format/webp/",
+		},
+		{name: "index_out_of_range", info: info, i: 3, err: cdn.ErrInvalidNth},
+		{name: "negative_index", info: info, i: -1, err: cdn.ErrInvalidNth},
+		{name: "invalid_op", info: info, ops: []cdn.Op{cdn.QualityOp{Quality: "bad"}}, err: cdn.ErrInvalidOp},
+		{
+			name: "cdn_link_with_path_prefix",
+			info: Info{ID: rewriteGroupID, FileCount: 3, CDNLink: "https://cdn.example.com/media/" + rewriteGroupID + "/"},
+			i:    1,
+			want: "https://cdn.example.com/media/" + rewriteGroupID + "/nth/1/",
+		},
+		{name: "no_cdn_link", info: Info{ID: rewriteGroupID, FileCount: 3}, err: ErrNoCDNURL},
+		{
+			name: "cdn_link_of_other_group",
+			info: Info{ID: rewriteGroupID, FileCount: 3, CDNLink: rewriteCDN + "/other~3/"},
+			err:  ErrNoCDNURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.info.FileURL(tt.i, tt.ops...)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInfoFileURL_CDNBaseWithPathPrefix(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uctest.RespondJSON(t, w, Info{ID: rewriteGroupID, FileCount: 3, CDNLink: legacyLink})
+	}), func(t *testing.T, srv *httptest.Server) {
+		c := uctest.NewServerClient(srv)
+		c.CDN = "https://cdn.example.com/media"
+		info, err := NewService(c).Info(context.Background(), rewriteGroupID)
+		require.NoError(t, err)
+
+		got, err := info.FileURL(1)
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn.example.com/media/"+rewriteGroupID+"/nth/1/", got)
+	})
+}
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/groups/" + rewriteGroupID + "/":
+			uctest.RespondJSON(t, w, Info{
+				ID:        rewriteGroupID,
+				FileCount: 3,
+				CDNLink:   "http://" + r.Host + "/" + rewriteGroupID + "/",
+			})
+		case "/" + rewriteGroupID + "/archive/zip/":
+			_, _ = w.Write([]byte("zip content"))
+		case "/" + rewriteGroupID + "/archive/tar/":
+			_, _ = w.Write([]byte("tar content"))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		cdn    bool
+		format ArchiveFormat
+		want   string
+	}{
+		{"zip", true, ArchiveZip, "zip content"},
+		{"tar", true, ArchiveTar, "tar content"},
+		{"cdn_link_fallback", false, ArchiveZip, "zip content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uctest.WithHTTPServer(t, handler, func(t *testing.T, srv *httptest.Server) {
+				c := uctest.NewServerClient(srv)
+				if tt.cdn {
+					c.CDN = srv.URL
+				}
+				archive, err := Archive(context.Background(), c, rewriteGroupID, tt.format)
+				require.NoError(t, err)
+				defer func() { _ = archive.Close() }()
+
+				got, err := io.ReadAll(archive)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, string(got))
+			})
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		uctest.WithHTTPServer(t, handler, func(t *testing.T, srv *httptest.Server) {
+			c := uctest.NewServerClient(srv)
+			c.CDN = srv.URL
+
+			_, err := Archive(context.Background(), c, rewriteGroupID, "rar")
+			assert.ErrorIs(t, err, ErrInvalidArchiveFormat)
+
+			_, err = Archive(context.Background(), c, "unknown~1", ArchiveZip)
+			var apiErr ucare.APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		})
+	})
+}
@@ -134,6 +134,9 @@ func TestList(t *testing.T) {
 		uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			raw := `{
 				"next": null,
+				"previous": null,
+				"total": 1,
+				"per_page": 100,
 				"results": [
 					{
 						"id": "abc~2",
@@ -150,6 +153,8 @@ func TestList(t *testing.T) {
 			svc := NewService(uctest.NewServerClient(srv))
 			list, err := svc.List(context.Background(), ListParams{})
 			require.NoError(t, err)
+			assert.Equal(t, uint64(1), list.Total())
+			assert.Equal(t, uint64(100), list.PerPage())
 
 			require.True(t, list.Next())
 			info, err := list.ReadResult()
@@ -166,4 +171,42 @@ func TestList(t *testing.T) {
 			assert.Equal(t, 15, info.CreatedAt.Day())
 		})
 	})
+	t.Run("resume_from_cursor", func(t *testing.T) {
+		t.Parallel()
+
+		uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/groups/", r.URL.Path)
+
+			resp := map[string]any{
+				"next":    "http://" + r.Host + "/groups/?page=2",
+				"results": []Info{{ID: "g1~1"}, {ID: "g2~1"}},
+			}
+			if r.URL.Query().Get("page") == "2" {
+				resp = map[string]any{
+					"next":    nil,
+					"results": []Info{{ID: "g3~1"}},
+				}
+			}
+			uctest.RespondJSON(t, w, resp)
+		}), func(t *testing.T, srv *httptest.Server) {
+			svc := NewService(uctest.NewServerClient(srv))
+			list, err := svc.List(context.Background(), ListParams{})
+			require.NoError(t, err)
+
+			_, err = list.ReadResult()
+			require.NoError(t, err)
+			cursor := list.Cursor()
+
+			resumed, err := svc.List(context.Background(), ListParams{Cursor: &cursor})
+			require.NoError(t, err)
+
+			var ids []string
+			for resumed.Next() {
+				info, err := resumed.ReadResult()
+				require.NoError(t, err)
+				ids = append(ids, info.ID)
+			}
+			assert.Equal(t, []string{"g2~1", "g3~1"}, ids)
+		})
+	})
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/uploadcare/uploadcare-go/v2/internal/codec"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
 )
 
 // ListParams holds all possible params for the List method
@@ -21,6 +22,11 @@ type ListParams struct {
 
 	// StartingFrom is a starting point for filtering group lists.
 	StartingFrom *time.Time `form:"from"`
+
+	// Cursor resumes the list from a token previously returned by
+	// List.Cursor. When set, the rest of the params are ignored as the
+	// cursor already holds the query the list has been started with.
+	Cursor *string
 }
 
 // EncodeReq implements ucare.ReqEncoder
@@ -30,13 +36,46 @@ func (d *ListParams) EncodeReq(req *http.Request) error {
 
 // List holds a list of files
 type List struct {
-	raw     codec.NextRawResulter
+	raw     codec.Pager
 	cdnBase string
 }
 
 // Next indicates if there is a result to read
 func (v *List) Next() bool { return v.raw.Next() }
 
+// Total returns the total number of groups in the list across all pages
+func (v *List) Total() uint64 { return v.raw.PageInfo().Total }
+
+// PerPage returns the number of groups returned per single page
+func (v *List) PerPage() uint64 { return v.raw.PageInfo().PerPage }
+
+// PageInfo returns details of the page currently being read, including
+// the links to the next and previous pages.
+func (v *List) PageInfo() ucare.PageInfo { return v.raw.PageInfo() }
+
+// Cursor returns a token pointing at the next result to read. Pass it as
+// ListParams.Cursor to a new List call to resume reading from that point,
+// e.g. after a process restart.
+func (v *List) Cursor() string { return v.raw.Cursor() }
+
+// Prefetch makes the list request up to depth upcoming pages in the
+// background while the current page is being read, so ReadResult does not
+// stall on page boundaries. Prefetched pages are requested through the same
+// client, honouring its retry settings, and prefetching stops once the
+// context passed to List is done. Zero depth turns prefetching off.
+//
+// Call Close if the list is abandoned before all groups are read and the
+// context is never canceled.
+func (v *List) Prefetch(depth int) { v.raw.Prefetch(depth) }
+
+// Close stops prefetching pages in the background
+func (v *List) Close() { v.raw.Close() }
+
+// PrevPage navigates the list one page back. Subsequent ReadResult calls
+// read the previous page from its beginning. It returns
+// ucare.ErrNoPrevPage if the current page is the first one.
+func (v *List) PrevPage() error { return v.raw.ReadPrevPage() }
+
 // ReadResult returns next Info value. If no results are left to read it
 // returns ucare.ErrEndOfResults.
 // Example usage:
@@ -76,6 +115,10 @@ func (v *List) ReadResult() (*Info, error) {
 //		...
 //	}
 func (s service) List(ctx context.Context, params ListParams) (*List, error) {
+	if params.Cursor != nil {
+		resbuf, err := s.svc.ListFrom(ctx, listPathFormat, *params.Cursor)
+		return &List{raw: resbuf, cdnBase: s.cdnBase}, err
+	}
 	resbuf, err := s.svc.List(ctx, listPathFormat, &params)
 	return &List{raw: resbuf, cdnBase: s.cdnBase}, err
 }
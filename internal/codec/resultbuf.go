@@ -0,0 +1,354 @@
+package codec
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// NextRawResulter abstracts reading raw results from paginated api response
+type NextRawResulter interface {
+	Next() bool
+	ReadRawResult() (Raw, error)
+}
+
+// Pager abstracts page level navigation over a paginated api response
+type Pager interface {
+	NextRawResulter
+	PageInfo() ucare.PageInfo
+	ReadPrevPage() error
+	Cursor() string
+	Prefetch(depth int)
+	Close()
+}
+
+// ResultBuf implements Pager
+type ResultBuf struct {
+	Ctx       context.Context
+	ReqMethod string
+	Client    ucare.Client
+
+	reading sync.Mutex // serializes ReadRawResult calls
+
+	sync.Mutex // guards everything below
+	page
+	at  int    // index to read from the Vals
+	url string // url the Vals have been requested from
+
+	depth    int                // number of pages to prefetch
+	prefetch <-chan fetchedPage // nil when pages are not prefetched
+	stop     context.CancelFunc // stops prefetching
+}
+
+// page is a single paginated api response
+type page struct {
+	NextPage *string `json:"next"`
+	PrevPage *string `json:"previous"`
+	Total    uint64  `json:"total"`
+	PerPage  uint64  `json:"per_page"`
+	Vals     []Raw   `json:"results"`
+}
+
+type fetchedPage struct {
+	page
+	url string
+	err error
+}
+
+// Next indicates if there is a result to read
+func (b *ResultBuf) Next() bool {
+	b.Lock()
+	defer b.Unlock()
+	return b.at < len(b.Vals) || b.NextPage != nil
+}
+
+// ErrEndOfResults denotes absence of results
+var ErrEndOfResults = errors.New("no results are left to read")
+
+// ReadRawResult reads returns next Raw result.
+// It makes paginated requests when all results from the current page
+// have been read.
+func (b *ResultBuf) ReadRawResult() (Raw, error) {
+	if !b.Next() {
+		return nil, ErrEndOfResults
+	}
+
+	b.reading.Lock()
+	defer b.reading.Unlock()
+	b.Lock()
+	defer b.Unlock()
+
+	if b.at >= len(b.Vals) && b.NextPage != nil {
+		if err := b.readNextPage(); err != nil {
+			return nil, err
+		}
+		if b.at >= len(b.Vals) {
+			return nil, ErrEndOfResults
+		}
+	}
+
+	res := b.Vals[b.at]
+	b.at++
+
+	return res, nil
+}
+
+// ReadPrevPage replaces buffered results with the results of the previous
+// page, so the following ReadRawResult calls start reading from its beginning.
+func (b *ResultBuf) ReadPrevPage() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.PrevPage == nil {
+		return ucare.ErrNoPrevPage
+	}
+	return b.fetch(*b.PrevPage)
+}
+
+// PageInfo returns the current page details
+func (b *ResultBuf) PageInfo() ucare.PageInfo {
+	b.Lock()
+	defer b.Unlock()
+
+	return ucare.PageInfo{
+		Total:    b.Total,
+		PerPage:  b.PerPage,
+		Current:  b.url,
+		Next:     b.NextPage,
+		Previous: b.PrevPage,
+	}
+}
+
+// Cursor returns a token pointing at the next result to read. The token
+// can be passed to Resume to continue reading from the same point.
+func (b *ResultBuf) Cursor() string {
+	b.Lock()
+	defer b.Unlock()
+
+	c := cursor{Page: b.url, Offset: b.at}
+	if b.at >= len(b.Vals) && b.NextPage != nil {
+		c = cursor{Page: *b.NextPage}
+	}
+	return c.encode()
+}
+
+// Resume fills the buffer with the page the cursor token points at and
+// skips results that have been read before the token was taken.
+func (b *ResultBuf) Resume(token string) error {
+	c, err := decodeCursor(token)
+	if err != nil {
+		return err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if err := b.fetch(c.Page); err != nil {
+		return err
+	}
+	b.at = min(c.Offset, len(b.Vals))
+	return nil
+}
+
+// Prefetch makes the buffer request up to depth upcoming pages in the
+// background while the current one is being read. Zero depth turns
+// prefetching off. Prefetching stops when the buffer context is done.
+func (b *ResultBuf) Prefetch(depth int) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.stopPrefetch()
+	b.depth = depth
+	b.startPrefetch()
+}
+
+// Close stops prefetching pages. It must be called if the results are
+// not read till the end and the buffer context is never canceled.
+func (b *ResultBuf) Close() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.stopPrefetch()
+	b.depth = 0
+}
+
+// ReadPage makes the req and fills the buffer with the page it returns
+func (b *ResultBuf) ReadPage(req *http.Request) error {
+	var p page
+	if err := b.Client.Do(req, &p); err != nil {
+		return err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.setPage(req.URL.String(), p)
+	return nil
+}
+
+// readNextPage takes the next page from the prefetched ones or requests
+// it if pages are not prefetched. b must be locked, it is unlocked while
+// waiting for a prefetched page, so the buffered page may be replaced
+// meanwhile, e.g. by ReadPrevPage.
+func (b *ResultBuf) readNextPage() error {
+	if b.prefetch == nil {
+		return b.fetch(*b.NextPage)
+	}
+
+	pages := b.prefetch
+	b.Unlock()
+	p, ok := <-pages
+	b.Lock()
+
+	if b.prefetch != pages {
+		// prefetching has been stopped or restarted while waiting, the
+		// page is stale
+		if b.at < len(b.Vals) || b.NextPage == nil {
+			return nil
+		}
+		return b.readNextPage()
+	}
+	if err := b.Ctx.Err(); err != nil {
+		// the page might have been sent before the goroutine noticed
+		// the context is done
+		b.stopPrefetch()
+		return err
+	}
+	if !ok {
+		b.stopPrefetch()
+		return b.fetch(*b.NextPage)
+	}
+	if p.err != nil {
+		b.stopPrefetch()
+		return p.err
+	}
+
+	b.setPage(p.url, p.page)
+	return nil
+}
+
+// fetch requests the page located at requrl. b must be locked.
+func (b *ResultBuf) fetch(requrl string) error {
+	b.stopPrefetch()
+
+	p, err := b.getPage(b.Ctx, requrl)
+	if err != nil {
+		return err
+	}
+
+	b.setPage(requrl, p)
+	b.startPrefetch()
+	return nil
+}
+
+// setPage replaces the buffered page. b must be locked.
+func (b *ResultBuf) setPage(requrl string, p page) {
+	b.page, b.url, b.at = p, requrl, 0
+}
+
+func (b *ResultBuf) getPage(ctx context.Context, requrl string) (p page, err error) {
+	u, err := url.Parse(requrl)
+	if err != nil {
+		return
+	}
+
+	req, err := b.Client.NewRequest(
+		ctx,
+		config.Endpoint(u.Host),
+		b.ReqMethod,
+		requrl,
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	err = b.Client.Do(req, &p)
+	return
+}
+
+// startPrefetch starts requesting pages following the current one.
+// b must be locked.
+func (b *ResultBuf) startPrefetch() {
+	if b.depth <= 0 || b.NextPage == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(b.Ctx)
+	// one more page is held by the goroutine while it waits to send it
+	pages := make(chan fetchedPage, b.depth-1)
+	b.prefetch, b.stop = pages, cancel
+
+	go func(next string) {
+		defer close(pages)
+		for {
+			p, err := b.getPage(ctx, next)
+			select {
+			case pages <- fetchedPage{page: p, url: next, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || p.NextPage == nil {
+				return
+			}
+			next = *p.NextPage
+		}
+	}(*b.NextPage)
+}
+
+// stopPrefetch stops the prefetching goroutine, if any, and waits for it
+// to exit. b must be locked.
+func (b *ResultBuf) stopPrefetch() {
+	if b.stop == nil {
+		return
+	}
+	b.stop()
+	for range b.prefetch {
+	}
+	b.prefetch, b.stop = nil, nil
+}
+
+// CursorPath returns the URL path of the page the cursor token points at
+func CursorPath(token string) (string, error) {
+	c, err := decodeCursor(token)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(c.Page)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ucare.ErrInvalidCursor, err)
+	}
+	return u.Path, nil
+}
+
+type cursor struct {
+	Page   string `json:"p"`
+	Offset int    `json:"o,omitempty"`
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (c cursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("%w: %s", ucare.ErrInvalidCursor, err)
+	}
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: %s", ucare.ErrInvalidCursor, err)
+	}
+	if c.Page == "" || c.Offset < 0 {
+		return c, ucare.ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -4,6 +4,7 @@ package svc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/uploadcare/uploadcare-go/v2/internal/codec"
@@ -46,7 +47,52 @@ func (s Service) List(
 		Client:    s.client,
 	}
 
-	return &resbuf, s.ResourceOp(ctx, method, path, params, &resbuf)
+	s.log.Infof("requesting: %s %s", method, path)
+
+	req, err := s.client.NewRequest(ctx, s.endpoint, method, path, params)
+	if err != nil {
+		return &resbuf, err
+	}
+
+	err = resbuf.ReadPage(req)
+	if err != nil {
+		return &resbuf, err
+	}
+
+	s.log.Debugf("received page: %s", req.URL)
+	return &resbuf, nil
+}
+
+// ListFrom resumes a list of raw results from the cursor token previously
+// returned by the *codec.ResultBuf of the list requested at path.
+func (s Service) ListFrom(
+	ctx context.Context,
+	path string,
+	cursor string,
+) (*codec.ResultBuf, error) {
+	method := http.MethodGet
+	resbuf := codec.ResultBuf{
+		Ctx:       ctx,
+		ReqMethod: method,
+		Client:    s.client,
+	}
+
+	cursorPath, err := codec.CursorPath(cursor)
+	if err != nil {
+		return &resbuf, err
+	}
+	if cursorPath != path {
+		return &resbuf, fmt.Errorf(
+			"%w: cursor points at %s, not %s",
+			ucare.ErrInvalidCursor,
+			cursorPath,
+			path,
+		)
+	}
+
+	s.log.Infof("resuming: %s %s", method, path)
+
+	return &resbuf, resbuf.Resume(cursor)
 }
 
 // ResourceOp operates on single resource. The response data is
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 
 	"github.com/uploadcare/uploadcare-go/v2/internal/config"
 	"github.com/uploadcare/uploadcare-go/v2/ucare"
@@ -34,7 +35,7 @@ func (c *Client) NewRequest(
 	method, requrl string,
 	data ucare.ReqEncoder,
 ) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+requrl, nil)
+	req, err := http.NewRequestWithContext(ctx, method, resolveURL(c.BaseURL, requrl), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +58,7 @@ func (c *Client) Do(req *http.Request, resdata interface{}) error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, body)
+		return ucare.APIError{StatusCode: resp.StatusCode, Detail: string(body)}
 	}
 
 	if resdata == nil || reflect.ValueOf(resdata).IsNil() {
@@ -66,6 +67,14 @@ func (c *Client) Do(req *http.Request, resdata interface{}) error {
 	return json.NewDecoder(resp.Body).Decode(resdata)
 }
 
+// resolveURL keeps absolute URLs (e.g. pagination links) intact
+func resolveURL(baseURL, requrl string) string {
+	if strings.HasPrefix(requrl, "http://") || strings.HasPrefix(requrl, "https://") {
+		return requrl
+	}
+	return baseURL + requrl
+}
+
 type UploadClient struct {
 	HTTP    *http.Client
 	BaseURL string
@@ -0,0 +1,440 @@
+// Package mirror keeps a local copy of the stored files of a project.
+//
+// Every stored file is downloaded from the CDN into its own directory named
+// by the file UUID and recorded into a manifest along with its info and
+// metadata. Subsequent syncs only list files uploaded since the previous
+// successful sync and skip the files already recorded, so an interrupted
+// sync resumes where it stopped:
+//
+//	stats, err := mirror.Sync(ctx, client, mirror.Params{Dir: "/backup/uploadcare"})
+//	if err != nil {
+//		// handle error, files recorded so far are kept
+//	}
+//	fmt.Printf("%d files downloaded\n", stats.Downloaded)
+//
+// The manifest is a snapshot along with a journal the mirrored files are
+// appended to while syncing. The journal is merged into the snapshot once
+// a sync completes and when the next sync starts after an interruption.
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/fanout"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// Names of the manifest files in the mirror directory
+const (
+	ManifestName = "manifest.json"
+	JournalName  = "manifest.journal"
+)
+
+// DefaultSaveEvery is used when Params.SaveEvery is not set
+const DefaultSaveEvery = 100
+
+// Params holds mirror sync params
+type Params struct {
+	// Dir is a local directory the files are mirrored into
+	Dir string
+
+	// HTTPClient is used to download files from the CDN. By default they
+	// are downloaded through the client, see ucare.ClientDownload.
+	HTTPClient *http.Client
+
+	// Concurrency limits the number of downloads running at once.
+	// Defaults to fanout.DefaultConcurrency.
+	Concurrency int
+
+	// SaveEvery is the maximum number of mirrored files appended to the
+	// journal between flushing it to disk. Defaults to DefaultSaveEvery.
+	SaveEvery int
+}
+
+// Manifest describes the mirrored files
+type Manifest struct {
+	// UploadedSince is the upload date of the latest file mirrored by the
+	// last successful sync, the next sync lists files starting from it
+	UploadedSince *time.Time `json:"uploaded_since,omitempty"`
+
+	// Files are the mirrored files keyed by file ID
+	Files map[string]Entry `json:"files"`
+}
+
+// Entry describes a mirrored file
+type Entry struct {
+	ID               string            `json:"uuid"`
+	OriginalFileName string            `json:"original_filename"`
+	MimeType         string            `json:"mime_type"`
+	Size             uint64            `json:"size"`
+	UploadedAt       *time.Time        `json:"datetime_uploaded,omitempty"`
+	StoredAt         *time.Time        `json:"datetime_stored,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+
+	// Path is the file path relative to the mirror directory
+	Path string `json:"path"`
+}
+
+// Stats holds sync results
+type Stats struct {
+	// Downloaded is the number of files downloaded
+	Downloaded int
+	// Skipped is the number of files mirrored before
+	Skipped int
+	// Unavailable is the number of files having no CDN URL to download
+	// them from. They are listed again by the next sync.
+	Unavailable int
+	// Failed is the number of files failed to download
+	Failed int
+}
+
+// ErrSizeMismatch is returned when a downloaded file size differs from the
+// size in the file info
+var ErrSizeMismatch = errors.New("mirror: downloaded file size mismatch")
+
+// LoadManifest reads the manifest of the mirror directory, including the
+// files journaled by an interrupted sync. It returns an empty manifest if
+// the directory has not been synced yet.
+func LoadManifest(dir string) (*Manifest, error) {
+	m, _, err := loadManifest(dir)
+	return m, err
+}
+
+// loadManifest returns the manifest and the number of journaled files
+func loadManifest(dir string) (*Manifest, int, error) {
+	m := Manifest{Files: map[string]Entry{}}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, 0, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, 0, fmt.Errorf("mirror: reading manifest: %w", err)
+		}
+		if m.Files == nil {
+			m.Files = map[string]Entry{}
+		}
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, JournalName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &m, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	n := 0
+	for len(data) > 0 {
+		line, rest, complete := bytes.Cut(data, []byte("\n"))
+		data = rest
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if !complete {
+				// the last entry was cut by an interruption
+				break
+			}
+			return nil, 0, fmt.Errorf("mirror: reading journal: %w", err)
+		}
+		m.Files[e.ID] = e
+		n++
+	}
+	return &m, n, nil
+}
+
+// save writes the manifest snapshot and removes the journal merged into it
+func (m *Manifest) save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ManifestName)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(dir, JournalName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Sync downloads stored files uploaded since the previous successful sync
+// into the mirror directory. Failed downloads do not stop the sync, their
+// errors are returned joined once the rest of the files are mirrored.
+// Mirrored files are journaled as they are downloaded, so the files
+// downloaded before an interruption are not downloaded again.
+func Sync(ctx context.Context, client ucare.Client, params Params) (Stats, error) {
+	if params.Dir == "" {
+		return Stats{}, errors.New("mirror: Dir is required")
+	}
+	if err := os.MkdirAll(params.Dir, 0o755); err != nil {
+		return Stats{}, err
+	}
+
+	m, journaled, err := loadManifest(params.Dir)
+	if err != nil {
+		return Stats{}, err
+	}
+	if journaled > 0 {
+		// merge the journal of the interrupted sync, so it only ever
+		// holds the files of a single sync
+		if err := m.save(params.Dir); err != nil {
+			return Stats{}, err
+		}
+	}
+
+	s := syncer{
+		Params:   params,
+		client:   client,
+		manifest: m,
+		since:    m.UploadedSince,
+	}
+	if s.SaveEvery <= 0 {
+		s.SaveEvery = DefaultSaveEvery
+	}
+
+	list, err := file.NewService(client).List(ctx, file.ListParams{
+		Stored:       ucare.Bool(true),
+		OrderBy:      ucare.String(file.OrderByUploadedAtAsc),
+		StartingFrom: m.UploadedSince,
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+	defer list.Close()
+
+	j, err := openJournal(params.Dir, s.SaveEvery)
+	if err != nil {
+		return Stats{}, err
+	}
+	s.journal = j
+
+	runErr := fanout.Run(ctx, list, s.mirror, fanout.Options[file.Info]{
+		Concurrency:     params.Concurrency,
+		ContinueOnError: true,
+	})
+	if err := j.close(); err != nil {
+		// the journal may miss files, keep it for the next sync to merge
+		return s.stats, errors.Join(runErr, err)
+	}
+
+	if runErr == nil {
+		// everything up to the latest file is mirrored, so the next
+		// sync can skip listing it
+		m.UploadedSince = s.since
+		if s.held != nil && (s.since == nil || s.held.Before(*s.since)) {
+			m.UploadedSince = s.held
+		}
+	}
+	if err := m.save(params.Dir); err != nil {
+		return s.stats, errors.Join(runErr, err)
+	}
+	return s.stats, runErr
+}
+
+type syncer struct {
+	Params
+	client  ucare.Client
+	journal *journal
+
+	mu       sync.Mutex // guards everything below
+	manifest *Manifest
+	since    *time.Time // upload date of the latest file seen
+	held     *time.Time // upload date of the earliest unavailable file
+	stats    Stats
+}
+
+func (s *syncer) mirror(ctx context.Context, info *file.Info) error {
+	s.mu.Lock()
+	if info.UploadedAt != nil && (s.since == nil || info.UploadedAt.After(*s.since)) {
+		t := info.UploadedAt.Time
+		s.since = &t
+	}
+	_, done := s.manifest.Files[info.ID]
+	switch {
+	case done:
+		s.stats.Skipped++
+	case info.OriginalFileURL == nil:
+		s.stats.Unavailable++
+		if info.UploadedAt != nil && (s.held == nil || info.UploadedAt.Before(*s.held)) {
+			t := info.UploadedAt.Time
+			s.held = &t
+		}
+	}
+	s.mu.Unlock()
+
+	if done || info.OriginalFileURL == nil {
+		return nil
+	}
+
+	entry := newEntry(info)
+	if err := s.download(ctx, *info.OriginalFileURL, entry.Path, info.Size); err != nil {
+		s.mu.Lock()
+		s.stats.Failed++
+		s.mu.Unlock()
+		return fmt.Errorf("mirror: file %s: %w", info.ID, err)
+	}
+
+	s.mu.Lock()
+	s.manifest.Files[info.ID] = entry
+	s.stats.Downloaded++
+	s.mu.Unlock()
+
+	s.journal.append(entry)
+	return nil
+}
+
+// journal appends mirrored files to the journal file in the background,
+// so the downloads never wait for the disk
+type journal struct {
+	entries chan Entry
+	done    chan error
+}
+
+func openJournal(dir string, flushEvery int) (*journal, error) {
+	f, err := os.OpenFile(
+		filepath.Join(dir, JournalName),
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+		0o644,
+	)
+	if err != nil {
+		return nil, err
+	}
+	j := &journal{
+		entries: make(chan Entry, flushEvery),
+		done:    make(chan error, 1),
+	}
+	go j.run(f, flushEvery)
+	return j, nil
+}
+
+func (j *journal) append(e Entry) { j.entries <- e }
+
+// close flushes the journal and returns the first write error, if any
+func (j *journal) close() error {
+	close(j.entries)
+	return <-j.done
+}
+
+// run writes the entries, flushing them once there are no more queued or
+// flushEvery of them are buffered
+func (j *journal) run(f *os.File, flushEvery int) {
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	var err error
+	unflushed := 0
+	for e := range j.entries {
+		if err != nil {
+			continue // drain the queue
+		}
+		if err = enc.Encode(e); err != nil {
+			continue
+		}
+		unflushed++
+		if unflushed >= flushEvery || len(j.entries) == 0 {
+			err = w.Flush()
+			unflushed = 0
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	j.done <- err
+}
+
+// download writes the file at rawURL into the path relative to the mirror
+// directory through a temporary file, so that incomplete downloads are
+// never taken for mirrored files
+func (s *syncer) download(ctx context.Context, rawURL, path string, size uint64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	var resp *http.Response
+	if s.HTTPClient != nil {
+		resp, err = s.HTTPClient.Do(req)
+	} else {
+		resp, err = ucare.ClientDownload(s.client, req)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", rawURL, resp.Status)
+	}
+
+	dst := filepath.Join(s.Dir, path)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	n, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if uint64(n) != size {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrSizeMismatch, n, size)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func newEntry(info *file.Info) Entry {
+	e := Entry{
+		ID:               info.ID,
+		OriginalFileName: info.OriginalFileName,
+		MimeType:         info.MimeType,
+		Size:             info.Size,
+		Metadata:         info.Metadata,
+		Path:             filepath.Join(info.ID, localName(info.OriginalFileName)),
+	}
+	if info.UploadedAt != nil {
+		e.UploadedAt = &info.UploadedAt.Time
+	}
+	if info.StoredAt != nil {
+		e.StoredAt = &info.StoredAt.Time
+	}
+	return e
+}
+
+// localName makes the original file name safe to be used as a local one
+func localName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+	return name
+}
@@ -0,0 +1,253 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+// project serves a file list and the file contents
+type project struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	files     []file.Info
+	contents  map[string]string
+	froms     []string
+	downloads []string
+	noURL     map[string]bool
+}
+
+func newProject(t *testing.T) *project {
+	return &project{t: t, contents: map[string]string{}, noURL: map[string]bool{}}
+}
+
+func (p *project) add(id, name, content string, size int, uploaded time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files = append(p.files, file.Info{
+		BasicFileInfo: file.BasicFileInfo{
+			ID:               id,
+			OriginalFileName: name,
+			Size:             uint64(size),
+			MimeType:         "text/plain",
+		},
+		UploadedAt: &config.Time{Time: uploaded},
+		Metadata:   map[string]string{"owner": "alice"},
+	})
+	p.contents[id] = content
+}
+
+func (p *project) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if r.URL.Path != "/files/" {
+		id := strings.Trim(r.URL.Path, "/")
+		p.downloads = append(p.downloads, id)
+		_, _ = w.Write([]byte(p.contents[id]))
+		return
+	}
+
+	from := r.URL.Query().Get("from")
+	p.froms = append(p.froms, from)
+	results := []file.Info{}
+	for _, fi := range p.files {
+		if from != "" && fi.UploadedAt.Format("2006-01-02T15:04:05") < from {
+			continue
+		}
+		if !p.noURL[fi.ID] {
+			u := fmt.Sprintf("http://%s/%s/", r.Host, fi.ID)
+			fi.OriginalFileURL = &u
+		}
+		results = append(results, fi)
+	}
+	uctest.RespondJSON(p.t, w, map[string]any{"next": nil, "results": results})
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	p := newProject(t)
+	p.add("a", "notes.txt", "hello", 5, day)
+	p.add("b", "../evil/name.txt", "world!", 6, day.Add(time.Hour))
+
+	uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+		client := uctest.NewServerClient(srv)
+		dir := t.TempDir()
+
+		stats, err := Sync(context.Background(), client, Params{Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Downloaded: 2}, stats)
+
+		data, err := os.ReadFile(filepath.Join(dir, "a", "notes.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+		data, err = os.ReadFile(filepath.Join(dir, "b", ".._evil_name.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "world!", string(data))
+
+		assert.NoFileExists(t, filepath.Join(dir, JournalName), "journal must be merged")
+		m, err := LoadManifest(dir)
+		require.NoError(t, err)
+		require.Len(t, m.Files, 2)
+		assert.Equal(t, map[string]string{"owner": "alice"}, m.Files["a"].Metadata)
+		assert.Equal(t, filepath.Join("a", "notes.txt"), m.Files["a"].Path)
+		require.NotNil(t, m.UploadedSince)
+		assert.True(t, m.UploadedSince.Equal(day.Add(time.Hour)))
+
+		p.add("c", "new.txt", "new", 3, day.Add(2*time.Hour))
+
+		stats, err = Sync(context.Background(), client, Params{Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Downloaded: 1, Skipped: 1}, stats)
+
+		assert.Equal(t, []string{"", "2024-05-01T01:00:00"}, p.froms)
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, p.downloads)
+	})
+}
+
+func TestSync_SizeMismatch(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	p := newProject(t)
+	p.add("a", "a.txt", "hello", 5, day)
+	p.add("b", "b.txt", "trunc", 10, day.Add(time.Hour))
+
+	uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+		client := uctest.NewServerClient(srv)
+		dir := t.TempDir()
+
+		stats, err := Sync(context.Background(), client, Params{Dir: dir, Concurrency: 1})
+		require.ErrorIs(t, err, ErrSizeMismatch)
+		assert.Equal(t, Stats{Downloaded: 1, Failed: 1}, stats)
+		assert.NoFileExists(t, filepath.Join(dir, "b", "b.txt"))
+
+		m, err := LoadManifest(dir)
+		require.NoError(t, err)
+		assert.Contains(t, m.Files, "a")
+		assert.NotContains(t, m.Files, "b")
+		assert.Nil(t, m.UploadedSince, "failed sync must be listed again")
+
+		entries, err := os.ReadDir(filepath.Join(dir, "b"))
+		require.NoError(t, err)
+		assert.Empty(t, entries, "temporary download left behind")
+	})
+}
+
+func TestSync_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	p := newProject(t)
+	p.add("a", "a.txt", "hello", 5, day)
+	p.add("b", "b.txt", "later", 5, day.Add(time.Hour))
+	p.add("c", "c.txt", "world", 5, day.Add(2*time.Hour))
+	p.noURL["b"] = true
+
+	uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+		client := uctest.NewServerClient(srv)
+		dir := t.TempDir()
+
+		stats, err := Sync(context.Background(), client, Params{Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Downloaded: 2, Unavailable: 1}, stats)
+
+		m, err := LoadManifest(dir)
+		require.NoError(t, err)
+		require.NotNil(t, m.UploadedSince)
+		assert.True(t, m.UploadedSince.Equal(day.Add(time.Hour)), "watermark must not pass the unavailable file")
+
+		p.mu.Lock()
+		delete(p.noURL, "b")
+		p.mu.Unlock()
+
+		stats, err = Sync(context.Background(), client, Params{Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Downloaded: 1, Skipped: 1}, stats)
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, p.downloads)
+	})
+}
+
+func TestLoadManifest_Journal(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	m := &Manifest{UploadedSince: &since, Files: map[string]Entry{"a": {ID: "a", Path: "a/a.txt"}}}
+	require.NoError(t, m.save(dir))
+
+	// an interrupted sync journaled a file and was cut writing the next one
+	journal := `{"uuid":"b","path":"b/b.txt"}` + "\n" + `{"uuid":"c","pa`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, JournalName), []byte(journal), 0o644))
+
+	got, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Entry{
+		"a": {ID: "a", Path: "a/a.txt"},
+		"b": {ID: "b", Path: "b/b.txt"},
+	}, got.Files)
+	assert.True(t, got.UploadedSince.Equal(since))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, JournalName), []byte("garbage\n"), 0o644))
+	_, err = LoadManifest(dir)
+	assert.ErrorContains(t, err, "mirror: reading journal")
+}
+
+func TestSync_MergesJournal(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	p := newProject(t)
+	p.add("a", "a.txt", "hello", 5, day)
+	p.add("b", "b.txt", "world", 5, day.Add(time.Hour))
+
+	uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+		dir := t.TempDir()
+		// "a" was mirrored by an interrupted sync
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, JournalName),
+			[]byte(`{"uuid":"a","path":"a/a.txt"}`+"\n"),
+			0o644,
+		))
+
+		stats, err := Sync(context.Background(), uctest.NewServerClient(srv), Params{Dir: dir, SaveEvery: 1})
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Downloaded: 1, Skipped: 1}, stats)
+		assert.Equal(t, []string{"b"}, p.downloads)
+
+		assert.NoFileExists(t, filepath.Join(dir, JournalName))
+		m, err := LoadManifest(dir)
+		require.NoError(t, err)
+		assert.Len(t, m.Files, 2)
+	})
+}
+
+func TestLocalName(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct{ in, out string }{
+		{"photo.jpg", "photo.jpg"},
+		{"dir/photo.jpg", "dir_photo.jpg"},
+		{`C:\photo.jpg`, "C:_photo.jpg"},
+		{"..", "file"},
+		{"", "file"},
+	} {
+		assert.Equal(t, c.out, localName(c.in), c.in)
+	}
+}
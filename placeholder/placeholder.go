@@ -0,0 +1,238 @@
+// Package placeholder generates low-quality image placeholders for
+// progressive image loading.
+//
+// A tiny preview of the image is fetched from the CDN, its blurhash is
+// computed and the preview itself is returned as a base64 data URI (LQIP).
+// The blurhash can be written into the file metadata to be served along
+// with the file info:
+//
+//	res, err := placeholder.Generate(ctx, client, fileID, placeholder.Params{
+//		MetadataKey: "blurhash",
+//	})
+//	if err != nil {
+//		// handle error
+//	}
+//	fmt.Println(res.Blurhash, res.LQIP)
+package placeholder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/uploadcare/uploadcare-go/v2/cdn"
+	"github.com/uploadcare/uploadcare-go/v2/fanout"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/metadata"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// Defaults used when Params fields are not set
+const (
+	DefaultSize        = 32
+	DefaultXComponents = 4
+	DefaultYComponents = 3
+)
+
+// maxPreviewBytes caps the preview download, tiny previews are far smaller
+const maxPreviewBytes = 1 << 20
+
+// Params holds placeholder generation params
+type Params struct {
+	// Size is the maximum width and height of the preview the placeholder
+	// is computed from. Defaults to DefaultSize.
+	Size int
+
+	// XComponents and YComponents are the numbers of the blurhash
+	// components, within 1..9. Default to DefaultXComponents and
+	// DefaultYComponents.
+	XComponents int
+	YComponents int
+
+	// MetadataKey is the file metadata key the blurhash is written to.
+	// The blurhash is not written when empty. LQIP is never written as it
+	// exceeds the metadata value length limit for all but tiny previews.
+	MetadataKey string
+}
+
+func (p Params) withDefaults() Params {
+	if p.Size <= 0 {
+		p.Size = DefaultSize
+	}
+	if p.XComponents == 0 {
+		p.XComponents = DefaultXComponents
+	}
+	if p.YComponents == 0 {
+		p.YComponents = DefaultYComponents
+	}
+	return p
+}
+
+// Result holds the placeholders of an image
+type Result struct {
+	// Blurhash is the blurhash of the image
+	Blurhash string
+	// LQIP is the preview JPEG as a data URI
+	LQIP string
+	// Width and Height are the preview dimensions
+	Width  int
+	Height int
+}
+
+// Generate fetches a tiny preview of the image through the client CDN base,
+// see ucare.ClientCDNBase, and computes its placeholders. If
+// Params.MetadataKey is set, the blurhash is written into the file metadata.
+func Generate(
+	ctx context.Context,
+	client ucare.Client,
+	uuid string,
+	params Params,
+) (Result, error) {
+	params = params.withDefaults()
+	if params.XComponents < 1 || params.XComponents > 9 ||
+		params.YComponents < 1 || params.YComponents > 9 {
+		return Result{}, ErrInvalidComponents
+	}
+
+	preview, err := fetchPreview(ctx, client, uuid, params.Size)
+	if err != nil {
+		return Result{}, err
+	}
+	img, err := jpeg.Decode(bytes.NewReader(preview))
+	if err != nil {
+		return Result{}, fmt.Errorf("placeholder: decoding preview of %s: %w", uuid, err)
+	}
+	hash, err := Blurhash(img, params.XComponents, params.YComponents)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Blurhash: hash,
+		LQIP:     "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(preview),
+		Width:    img.Bounds().Dx(),
+		Height:   img.Bounds().Dy(),
+	}
+
+	if params.MetadataKey != "" {
+		_, err = metadata.NewService(client).Set(ctx, uuid, params.MetadataKey, hash)
+		if err != nil {
+			return res, err
+		}
+		log.Debugf("blurhash of %s written to metadata key %s", uuid, params.MetadataKey)
+	}
+	return res, nil
+}
+
+func fetchPreview(ctx context.Context, client ucare.Client, uuid string, size int) ([]byte, error) {
+	cdnBase := ucare.ClientCDNBase(client)
+	if cdnBase == "" {
+		return nil, cdn.ErrNoCDNBase
+	}
+	rawURL, err := cdn.New(uuid).
+		Preview(size, size).
+		Format(cdn.FormatJPEG).
+		Quality(cdn.QualityLightest).
+		URLWithBase(cdnBase)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("requesting preview %s", rawURL)
+
+	resp, err := ucare.ClientDownload(client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		detail := strings.TrimSpace(string(body))
+		if detail == "" {
+			detail = resp.Status
+		}
+		return nil, ucare.APIError{StatusCode: resp.StatusCode, Detail: detail}
+	}
+
+	preview, err := io.ReadAll(io.LimitReader(resp.Body, maxPreviewBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(preview) > maxPreviewBytes {
+		return nil, errors.New("placeholder: preview is too large")
+	}
+	return preview, nil
+}
+
+// AllParams holds params for the GenerateAll function
+type AllParams struct {
+	Params
+
+	// ListParams are passed to file.List. Unless ListParams.Filter is set,
+	// only images are listed.
+	ListParams file.ListParams
+
+	// Overwrite makes GenerateAll process the files already having the
+	// Params.MetadataKey set. By default they are skipped.
+	Overwrite bool
+
+	// Concurrency limits the number of files processed at once.
+	// Defaults to fanout.DefaultConcurrency.
+	Concurrency int
+}
+
+// GenerateAll generates placeholders of the images listed from the project
+// and returns them by file UUID. A failure for a single file does not stop
+// the rest from being processed, failures are returned as
+// fanout.Errors[file.Info] along with the results of the succeeded files.
+func GenerateAll(
+	ctx context.Context,
+	client ucare.Client,
+	params AllParams,
+) (map[string]Result, error) {
+	listParams := params.ListParams
+	if listParams.Filter == nil {
+		listParams.Filter = file.Where().Image(true)
+	}
+	if key := params.MetadataKey; key != "" && !params.Overwrite {
+		listParams.Filter = listParams.Filter.Func(func(info *file.Info) bool {
+			_, ok := info.Metadata[key]
+			return !ok
+		})
+	}
+
+	list, err := file.NewService(client).List(ctx, listParams)
+	if err != nil {
+		return nil, err
+	}
+	defer list.Close()
+
+	var mu sync.Mutex
+	results := make(map[string]Result)
+	err = fanout.Run(ctx, list, func(ctx context.Context, info *file.Info) error {
+		res, err := Generate(ctx, client, info.ID, params.Params)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		results[info.ID] = res
+		mu.Unlock()
+		return nil
+	}, fanout.Options[file.Info]{
+		Concurrency:     params.Concurrency,
+		ContinueOnError: true,
+	})
+	return results, err
+}
@@ -0,0 +1,175 @@
+package placeholder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/cdn"
+	"github.com/uploadcare/uploadcare-go/v2/fanout"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+const (
+	testUUID  = "a5ed4c7a-d1b6-4bb8-9e0e-6a1a6e6b2b5c"
+	otherUUID = "b7f1d0a2-3c4e-4f5a-8b6c-7d8e9f0a1b2c"
+	brokenID  = "c9e2f1b3-4d5f-4a6b-9c7d-8e9f0a1b2c3d"
+)
+
+// gradient returns an 8x6 image with red growing to the right and green
+// growing to the bottom
+func gradient() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 6))
+	for y := range 6 {
+		for x := range 8 {
+			img.Set(x, y, color.RGBA{uint8(x * 32), uint8(y * 40), 128, 255})
+		}
+	}
+	return img
+}
+
+func TestBlurhash(t *testing.T) {
+	t.Parallel()
+
+	// expected values are computed with an independent implementation
+	// of the reference algorithm
+	tests := []struct {
+		name   string
+		x, y   int
+		expect string
+	}{
+		{"4x3", 4, 3, "LjF=ad3Ba|xuzONLfQnTeqf7fQf7"},
+		{"1x1", 1, 1, "00F=ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := Blurhash(gradient(), tt.x, tt.y)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, hash)
+		})
+	}
+
+	t.Run("invalid components", func(t *testing.T) {
+		t.Parallel()
+
+		for _, c := range [][2]int{{0, 3}, {4, 10}} {
+			_, err := Blurhash(gradient(), c[0], c[1])
+			assert.ErrorIs(t, err, ErrInvalidComponents)
+		}
+	})
+}
+
+type testServer struct {
+	t       *testing.T
+	preview []byte
+
+	mu  sync.Mutex
+	set map[string]string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, gradient(), &jpeg.Options{Quality: 90}))
+	return &testServer{t: t, preview: buf.Bytes(), set: make(map[string]string)}
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/files/":
+		uctest.RespondJSON(s.t, w, map[string]any{"next": nil, "results": []map[string]any{
+			{"uuid": testUUID, "is_image": true},
+			{"uuid": otherUUID, "is_image": true, "metadata": map[string]string{"blurhash": "x"}},
+			{"uuid": brokenID, "is_image": true},
+			{"uuid": "d0f3a2c4-5e6a-4b7c-8d8e-9f0a1b2c3d4e", "is_image": false},
+		}})
+	case strings.HasSuffix(r.URL.Path, "/metadata/blurhash/") && r.Method == http.MethodPut:
+		id := strings.Split(r.URL.Path, "/")[2]
+		value := strings.Trim(string(uctest.ReadBody(s.t, r)), `"`)
+		s.mu.Lock()
+		s.set[id] = value
+		s.mu.Unlock()
+		uctest.RespondJSON(s.t, w, value)
+	case r.URL.Path == "/"+brokenID+"/-/preview/32x32/-/format/jpeg/-/quality/lightest/":
+		_, _ = w.Write([]byte("not a jpeg"))
+	case strings.HasSuffix(r.URL.Path, "/-/preview/32x32/-/format/jpeg/-/quality/lightest/"):
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(s.preview)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func newTestClient(srv *httptest.Server) ucare.Client {
+	client := uctest.NewServerClient(srv)
+	client.CDN = srv.URL
+	return client
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+	uctest.WithHTTPServer(t, ts, func(t *testing.T, srv *httptest.Server) {
+		ctx := context.Background()
+		client := newTestClient(srv)
+
+		res, err := Generate(ctx, client, testUUID, Params{MetadataKey: "blurhash"})
+		require.NoError(t, err)
+
+		img, err := jpeg.Decode(bytes.NewReader(ts.preview))
+		require.NoError(t, err)
+		expect, err := Blurhash(img, DefaultXComponents, DefaultYComponents)
+		require.NoError(t, err)
+
+		assert.Equal(t, expect, res.Blurhash)
+		assert.Equal(t, "data:image/jpeg;base64,"+base64.StdEncoding.EncodeToString(ts.preview), res.LQIP)
+		assert.Equal(t, 8, res.Width)
+		assert.Equal(t, 6, res.Height)
+		assert.Equal(t, map[string]string{testUUID: expect}, ts.set)
+
+		_, err = Generate(ctx, client, "unknown", Params{})
+		assert.ErrorIs(t, err, cdn.ErrInvalidUUID)
+
+		_, err = Generate(ctx, client, testUUID, Params{XComponents: 10})
+		assert.ErrorIs(t, err, ErrInvalidComponents)
+
+		_, err = Generate(ctx, &uctest.Client{}, testUUID, Params{})
+		assert.ErrorIs(t, err, cdn.ErrNoCDNBase)
+	})
+}
+
+func TestGenerateAll(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+	uctest.WithHTTPServer(t, ts, func(t *testing.T, srv *httptest.Server) {
+		results, err := GenerateAll(context.Background(), newTestClient(srv), AllParams{
+			Params:      Params{MetadataKey: "blurhash"},
+			Concurrency: 2,
+		})
+
+		var errs fanout.Errors[file.Info]
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		assert.Equal(t, brokenID, errs[0].Item.ID)
+
+		require.Len(t, results, 1)
+		assert.NotEmpty(t, results[testUUID].Blurhash)
+		assert.Equal(t, map[string]string{testUUID: results[testUUID].Blurhash}, ts.set)
+	})
+}
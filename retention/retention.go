@@ -0,0 +1,318 @@
+// Package retention deletes project files according to declared policies.
+//
+// A policy selects files by their storage state, age and any file.Filter
+// conditions, e.g. file metadata. Files are evaluated against the policies
+// by iterating file.List, the report of the files to delete can be reviewed
+// before the deletion:
+//
+//	params := retention.Params{
+//		Policies: []retention.Policy{{
+//			Name:   "temporary",
+//			Stored: ucare.Bool(false),
+//			MinAge: 12 * time.Hour,
+//		}, {
+//			Name:   "previews",
+//			Stored: ucare.Bool(true),
+//			MinAge: 30 * 24 * time.Hour,
+//			Filter: file.Where().Metadata("env", "preview"),
+//		}},
+//		Keep:  keepIDs,
+//		Audit: retention.JSONAudit(auditLog),
+//	}
+//	report, err := retention.Evaluate(ctx, fileSvc, params)
+//	if err != nil {
+//		// handle error
+//	}
+//	// review report.Candidates
+//	report, err = retention.Run(ctx, fileSvc, params)
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/upload"
+)
+
+// ErrInvalidPolicy is returned for policies without a name or conditions
+var ErrInvalidPolicy = errors.New("invalid retention policy")
+
+// Policy declares files to be deleted. A file is selected by the policy
+// when it matches all of the set conditions.
+type Policy struct {
+	// Name identifies the policy in reports and the audit trail
+	Name string
+
+	// Stored selects stored files if true and temporary files if false.
+	// Both are selected if unset.
+	Stored *bool
+
+	// MinAge selects files uploaded more than MinAge ago
+	MinAge time.Duration
+
+	// Filter selects files matching it, e.g. by metadata
+	Filter *file.Filter
+}
+
+// Match reports whether the file is selected by the policy at the moment now
+func (p Policy) Match(info *file.Info, now time.Time) bool {
+	if p.Stored != nil && *p.Stored != (info.StoredAt != nil) {
+		return false
+	}
+	if p.MinAge > 0 {
+		if info.UploadedAt == nil || !info.UploadedAt.Before(now.Add(-p.MinAge)) {
+			return false
+		}
+	}
+	return p.Filter == nil || p.Filter.Match(info)
+}
+
+func (p Policy) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPolicy)
+	}
+	if p.Stored == nil && p.MinAge <= 0 && p.Filter == nil {
+		return fmt.Errorf("%w: policy %q selects every file", ErrInvalidPolicy, p.Name)
+	}
+	return nil
+}
+
+// Params holds retention params
+type Params struct {
+	// Policies are matched against every file in order, the first
+	// matching policy is reported as the reason of the file deletion
+	Policies []Policy
+
+	// Keep holds IDs of the files never to delete, see GroupFileIDs
+	Keep []string
+
+	// Now is the moment the file ages are measured at.
+	// Defaults to the current time.
+	Now time.Time
+
+	// Batch configures the batch delete requests made by Run
+	Batch file.BatchAllParams
+
+	// Audit records the outcome of every deletion made by Run
+	Audit Audit
+}
+
+// Candidate is a file selected for deletion
+type Candidate struct {
+	Info file.Info
+	// Policy is the name of the policy selecting the file
+	Policy string
+}
+
+// Report holds the retention results
+type Report struct {
+	// Scanned is the number of listed files
+	Scanned int
+	// Kept is the number of selected files skipped as listed in Params.Keep
+	Kept int
+	// Candidates are the files selected for deletion
+	Candidates []Candidate
+
+	// Deleted holds IDs of the deleted files. It is set by Run only.
+	Deleted []string
+	// Problems holds the reasons the API gave for not deleting files,
+	// keyed by file ID. It is set by Run only.
+	Problems map[string]string
+}
+
+// Evaluate lists the project files and reports the files selected by the
+// policies without deleting them
+func Evaluate(ctx context.Context, svc file.Service, params Params) (*Report, error) {
+	for _, p := range params.Policies {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+	now := params.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	keep := make(map[string]bool, len(params.Keep))
+	for _, id := range params.Keep {
+		keep[id] = true
+	}
+
+	list, err := svc.List(ctx, listParams(params.Policies, now))
+	if err != nil {
+		return nil, err
+	}
+	defer list.Close()
+
+	report := Report{Candidates: []Candidate{}}
+	for list.Next() {
+		info, err := list.ReadResult()
+		if err != nil {
+			return &report, err
+		}
+		report.Scanned++
+
+		for _, p := range params.Policies {
+			if !p.Match(info, now) {
+				continue
+			}
+			if keep[info.ID] {
+				report.Kept++
+			} else {
+				report.Candidates = append(report.Candidates, Candidate{
+					Info:   *info,
+					Policy: p.Name,
+				})
+			}
+			break
+		}
+	}
+	return &report, nil
+}
+
+// listParams narrows the listing to files old enough for any policy to
+// select them, if every policy has the minimal age set
+func listParams(policies []Policy, now time.Time) file.ListParams {
+	var minAge time.Duration
+	for _, p := range policies {
+		if p.MinAge <= 0 {
+			return file.ListParams{}
+		}
+		if minAge == 0 || p.MinAge < minAge {
+			minAge = p.MinAge
+		}
+	}
+	if minAge == 0 {
+		return file.ListParams{}
+	}
+	return file.ListParams{
+		Filter: file.Where().UploadedBetween(time.Time{}, now.Add(-minAge)),
+	}
+}
+
+// Run evaluates the policies and deletes the selected files in chunked
+// batch requests. Every deletion outcome is recorded to Params.Audit.
+// Failed batch requests are reported with the returned
+// *file.BatchAllError, the deleted files are reported either way.
+func Run(ctx context.Context, svc file.Service, params Params) (*Report, error) {
+	report, err := Evaluate(ctx, svc, params)
+	if err != nil || len(report.Candidates) == 0 {
+		return report, err
+	}
+
+	ids := make([]string, len(report.Candidates))
+	for i, c := range report.Candidates {
+		ids[i] = c.Info.ID
+	}
+	res, runErr := file.BatchDeleteAll(ctx, svc, ids, &params.Batch)
+
+	report.Deleted = []string{}
+	report.Problems = res.Problems
+	deleted := make(map[string]bool, len(res.Results))
+	for _, fi := range res.Results {
+		deleted[fi.ID] = true
+	}
+
+	if params.Audit == nil {
+		params.Audit = discardAudit{}
+	}
+	at := time.Now()
+	var auditErr error
+	for _, c := range report.Candidates {
+		ev := Event{
+			Time:             at,
+			FileID:           c.Info.ID,
+			OriginalFileName: c.Info.OriginalFileName,
+			Size:             c.Info.Size,
+			Policy:           c.Policy,
+		}
+		switch problem, ok := res.Problems[c.Info.ID]; {
+		case deleted[c.Info.ID]:
+			ev.Action = ActionDeleted
+			report.Deleted = append(report.Deleted, c.Info.ID)
+		case ok:
+			ev.Action, ev.Reason = ActionProblem, problem
+		default:
+			ev.Action = ActionFailed
+			if runErr != nil {
+				ev.Reason = runErr.Error()
+			}
+		}
+		if err := params.Audit.Record(ev); err != nil && auditErr == nil {
+			auditErr = fmt.Errorf("recording audit event: %w", err)
+		}
+	}
+	return report, errors.Join(runErr, auditErr)
+}
+
+// Actions recorded to the audit trail
+const (
+	ActionDeleted = "deleted"
+	ActionProblem = "problem"
+	ActionFailed  = "failed"
+)
+
+// Event is an audit trail record of a file deletion attempt
+type Event struct {
+	Time             time.Time `json:"time"`
+	FileID           string    `json:"uuid"`
+	OriginalFileName string    `json:"original_filename"`
+	Size             uint64    `json:"size"`
+	Policy           string    `json:"policy"`
+	Action           string    `json:"action"`
+	// Reason tells why the file has not been deleted
+	Reason string `json:"reason,omitempty"`
+}
+
+// Audit records deletion attempts
+type Audit interface {
+	Record(Event) error
+}
+
+type discardAudit struct{}
+
+func (discardAudit) Record(Event) error { return nil }
+
+// JSONAudit returns an Audit writing events to w as JSON Lines
+func JSONAudit(w io.Writer) Audit {
+	return &jsonAudit{enc: json.NewEncoder(w)}
+}
+
+type jsonAudit struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (a *jsonAudit) Record(ev Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.enc.Encode(ev)
+}
+
+// GroupFileIDs returns IDs of the files in the groups, to be passed as
+// Params.Keep
+func GroupFileIDs(
+	ctx context.Context,
+	svc upload.Service,
+	groupIDs ...string,
+) ([]string, error) {
+	var ids []string
+	for _, groupID := range groupIDs {
+		info, err := svc.GroupInfo(ctx, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", groupID, err)
+		}
+		for _, fi := range info.Files {
+			// removed files are listed as nulls
+			if fi.ID != "" {
+				ids = append(ids, fi.ID)
+			}
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,163 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+	"github.com/uploadcare/uploadcare-go/v2/upload"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func testFile(id string, age time.Duration, stored bool, meta map[string]string) file.Info {
+	fi := file.Info{
+		BasicFileInfo: file.BasicFileInfo{ID: id, OriginalFileName: id + ".jpg", Size: 10},
+		UploadedAt:    &config.Time{Time: now.Add(-age)},
+		Metadata:      meta,
+	}
+	if stored {
+		fi.StoredAt = fi.UploadedAt
+	}
+	return fi
+}
+
+var testPolicies = []Policy{{
+	Name:   "temporary",
+	Stored: ucare.Bool(false),
+	MinAge: 12 * time.Hour,
+}, {
+	Name:   "previews",
+	Stored: ucare.Bool(true),
+	MinAge: 30 * 24 * time.Hour,
+	Filter: file.Where().Metadata("env", "preview"),
+}}
+
+// project serves the files in the upload order and deletes them in batches
+func project(t *testing.T, files []file.Info, deleted *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/files/":
+			uctest.RespondJSON(t, w, map[string]any{"next": nil, "results": files})
+		case r.Method == http.MethodDelete && r.URL.Path == "/files/storage/":
+			var ids []string
+			require.NoError(t, json.Unmarshal(uctest.ReadBody(t, r), &ids))
+			data := file.BatchInfo{Problems: map[string]string{}}
+			for _, id := range ids {
+				if id == "locked" {
+					data.Problems[id] = "File is locked"
+					continue
+				}
+				*deleted = append(*deleted, id)
+				data.Results = append(data.Results, file.Info{BasicFileInfo: file.BasicFileInfo{ID: id}})
+			}
+			uctest.RespondJSON(t, w, data)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	files := []file.Info{
+		testFile("old-preview", 40*24*time.Hour, true, map[string]string{"env": "preview"}),
+		testFile("old-prod", 40*24*time.Hour, true, map[string]string{"env": "prod"}),
+		testFile("kept-temp", 20*time.Hour, false, nil),
+		testFile("old-temp", 13*time.Hour, false, nil),
+		testFile("new-temp", time.Hour, false, nil),
+	}
+
+	var deleted []string
+	uctest.WithHTTPServer(t, project(t, files, &deleted), func(t *testing.T, srv *httptest.Server) {
+		report, err := Evaluate(context.Background(), file.NewService(uctest.NewServerClient(srv)), Params{
+			Policies: testPolicies,
+			Keep:     []string{"kept-temp"},
+			Now:      now,
+		})
+		require.NoError(t, err)
+
+		var ids, policies []string
+		for _, c := range report.Candidates {
+			ids = append(ids, c.Info.ID)
+			policies = append(policies, c.Policy)
+		}
+		assert.Equal(t, []string{"old-preview", "old-temp"}, ids)
+		assert.Equal(t, []string{"previews", "temporary"}, policies)
+		assert.Equal(t, 1, report.Kept)
+		assert.Equal(t, 4, report.Scanned, "listing must stop at files too new for any policy")
+		assert.Empty(t, deleted)
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	files := []file.Info{
+		testFile("a", 13*time.Hour, false, nil),
+		testFile("locked", 13*time.Hour, false, nil),
+		testFile("b", 13*time.Hour, false, nil),
+	}
+
+	var deleted []string
+	uctest.WithHTTPServer(t, project(t, files, &deleted), func(t *testing.T, srv *httptest.Server) {
+		var audit bytes.Buffer
+		report, err := Run(context.Background(), file.NewService(uctest.NewServerClient(srv)), Params{
+			Policies: testPolicies,
+			Now:      now,
+			Audit:    JSONAudit(&audit),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"a", "b"}, deleted)
+		assert.Equal(t, []string{"a", "b"}, report.Deleted)
+		assert.Equal(t, map[string]string{"locked": "File is locked"}, report.Problems)
+
+		lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+		require.Len(t, lines, 3)
+		var ev Event
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &ev))
+		assert.Equal(t, "locked", ev.FileID)
+		assert.Equal(t, "temporary", ev.Policy)
+		assert.Equal(t, ActionProblem, ev.Action)
+		assert.Equal(t, "File is locked", ev.Reason)
+	})
+}
+
+func TestEvaluate_InvalidPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []Policy{
+		{Stored: ucare.Bool(true)},
+		{Name: "everything"},
+	} {
+		_, err := Evaluate(context.Background(), nil, Params{Policies: []Policy{p}})
+		assert.ErrorIs(t, err, ErrInvalidPolicy)
+	}
+}
+
+func TestGroupFileIDs(t *testing.T) {
+	t.Parallel()
+
+	uctest.WithHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "g~3", r.URL.Query().Get("group_id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "g~3", "files": [{"uuid": "a"}, null, {"uuid": "c"}]}`))
+	}), func(t *testing.T, srv *httptest.Server) {
+		ids, err := GroupFileIDs(context.Background(), upload.NewService(uctest.NewUploadServerClient(srv)), "g~3")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "c"}, ids)
+	})
+}
@@ -0,0 +1,80 @@
+package trash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotTrashed is returned by Store.Load for files not in the trash
+var ErrNotTrashed = errors.New("trash: file is not trashed")
+
+// Store keeps records of trashed files keyed by the deleted file ID
+type Store interface {
+	Save(ctx context.Context, rec Record) error
+	// Load returns ErrNotTrashed if there is no record of the file
+	Load(ctx context.Context, id string) (Record, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// DirStore is a Store keeping records as JSON files in the directory
+type DirStore string
+
+func (d DirStore) path(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return "", fmt.Errorf("trash: invalid file ID %q", id)
+	}
+	return filepath.Join(string(d), id+".json"), nil
+}
+
+// Save implements Store
+func (d DirStore) Save(_ context.Context, rec Record) error {
+	path, err := d.path(rec.Info.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(string(d), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Load implements Store
+func (d DirStore) Load(_ context.Context, id string) (rec Record, err error) {
+	path, err := d.path(id)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rec, fmt.Errorf("%w: %s", ErrNotTrashed, id)
+	}
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &rec)
+	return
+}
+
+// Delete implements Store
+func (d DirStore) Delete(_ context.Context, id string) error {
+	path, err := d.path(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
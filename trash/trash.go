@@ -0,0 +1,287 @@
+// Package trash makes file deletions reversible.
+//
+// Before a file is deleted, it is copied either to a custom storage or
+// within the project storage, where the copy is marked with the
+// TombstoneKey metadata. The original file info and metadata are recorded
+// into a Store, so the file can be restored later:
+//
+//	bin := trash.New(client, trash.DirStore("/var/lib/app/trash"), trash.Params{})
+//	if err := bin.Delete(ctx, fileID); err != nil {
+//		// handle error, the file is not deleted
+//	}
+//	...
+//	info, err := bin.Restore(ctx, fileID)
+//	if err != nil {
+//		// handle error
+//	}
+//	fmt.Printf("file is restored as %s\n", info.ID)
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/fanout"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/metadata"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+	"github.com/uploadcare/uploadcare-go/v2/upload"
+)
+
+// TombstoneKey is the metadata key marking project storage copies of
+// trashed files. Its value is the ID of the deleted file.
+const TombstoneKey = "trash_original_uuid"
+
+// ErrNoRemoteURL is returned when restoring a file copied to a custom
+// storage without Params.RemoteURL set
+var ErrNoRemoteURL = errors.New("trash: RemoteURL is required to restore files from a custom storage")
+
+// Params holds trash params
+type Params struct {
+	// Target is the name of a custom storage trashed files are copied to.
+	// If empty, files are copied within the project storage.
+	Target string
+
+	// RemoteURL maps the custom storage location of a trashed file, e.g.
+	// s3://bucket/path, to a public URL the file is uploaded back from on
+	// restore. Files copied to a custom storage can not be restored
+	// without it.
+	RemoteURL func(location string) (string, error)
+
+	// Concurrency limits the number of files copied at once by
+	// BatchDelete. Defaults to fanout.DefaultConcurrency.
+	Concurrency int
+}
+
+// Record holds details of a trashed file
+type Record struct {
+	// Info is the info of the deleted file
+	Info file.Info `json:"info"`
+
+	// Metadata is the metadata of the deleted file
+	Metadata map[string]string `json:"metadata"`
+
+	// CopyID is the ID of the project storage copy
+	CopyID string `json:"copy_id,omitempty"`
+
+	// Location is the custom storage location of the copy
+	Location string `json:"location,omitempty"`
+
+	// DeletedAt is the time the file was trashed at
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Trash deletes files keeping their copies to restore them from
+type Trash struct {
+	files    file.Service
+	metadata metadata.Service
+	upload   upload.Service
+	store    Store
+	params   Params
+}
+
+// New returns a Trash recording trashed files into the store
+func New(client ucare.Client, store Store, params Params) *Trash {
+	return &Trash{
+		files:    file.NewService(client),
+		metadata: metadata.NewService(client),
+		upload:   upload.NewService(client),
+		store:    store,
+		params:   params,
+	}
+}
+
+// Delete copies the file, records it into the store and deletes it.
+// The file is not deleted if any of the preceding steps fails.
+func (t *Trash) Delete(ctx context.Context, id string) error {
+	if err := t.trash(ctx, id); err != nil {
+		return err
+	}
+	if _, err := t.files.Delete(ctx, id); err != nil {
+		return fmt.Errorf("trash: deleting file %s: %w", id, err)
+	}
+	return nil
+}
+
+// BatchDelete trashes files concurrently and deletes them in chunked batch
+// requests. Only the files copied and recorded successfully are deleted,
+// the rest are reported with the returned error.
+func (t *Trash) BatchDelete(ctx context.Context, ids []string) (file.BatchInfo, error) {
+	var trashed []string
+	trashErr := fanout.Run(ctx, fanout.Slice(ids), func(ctx context.Context, id *string) error {
+		return t.trash(ctx, *id)
+	}, fanout.Options[string]{
+		Concurrency:     t.params.Concurrency,
+		ContinueOnError: true,
+		Ordered:         true,
+		OnDone: func(_ int, id *string, err error) {
+			if err == nil {
+				trashed = append(trashed, *id)
+			}
+		},
+	})
+	if len(trashed) == 0 {
+		return file.BatchInfo{}, trashErr
+	}
+
+	data, err := file.BatchDeleteAll(ctx, t.files, trashed, nil)
+	return data, errors.Join(trashErr, err)
+}
+
+func (t *Trash) trash(ctx context.Context, id string) error {
+	info, err := t.files.Info(ctx, id, nil)
+	if err != nil {
+		return fmt.Errorf("trash: file %s info: %w", id, err)
+	}
+	rec := Record{
+		Info:      info,
+		Metadata:  info.Metadata,
+		DeletedAt: time.Now().UTC(),
+	}
+	if rec.Metadata == nil {
+		if rec.Metadata, err = t.metadata.List(ctx, id); err != nil {
+			return fmt.Errorf("trash: file %s metadata: %w", id, err)
+		}
+	}
+
+	if t.params.Target != "" {
+		res, err := t.files.RemoteCopy(ctx, file.RemoteCopyParams{
+			Source:     id,
+			Target:     t.params.Target,
+			MakePublic: ucare.String(file.MakePublicFalse),
+		})
+		if err != nil {
+			return fmt.Errorf("trash: copying file %s: %w", id, err)
+		}
+		if res.Result != nil {
+			rec.Location = *res.Result
+		}
+	} else {
+		res, err := t.files.LocalCopy(ctx, file.LocalCopyParams{
+			Source: id,
+			Store:  ucare.String(file.StoreTrue),
+		})
+		if err != nil {
+			return fmt.Errorf("trash: copying file %s: %w", id, err)
+		}
+		rec.CopyID = res.Result.ID
+		if _, err := t.metadata.Set(ctx, rec.CopyID, TombstoneKey, id); err != nil {
+			return t.discardCopy(ctx, rec, fmt.Errorf("trash: marking copy of file %s: %w", id, err))
+		}
+	}
+
+	if err := t.store.Save(ctx, rec); err != nil {
+		return t.discardCopy(ctx, rec, fmt.Errorf("trash: recording file %s: %w", id, err))
+	}
+	return nil
+}
+
+// discardCopy deletes the project storage copy of a file failed to be
+// trashed, so it is neither left untracked nor copied again on retry.
+// Copies in the remote storage can not be deleted through the API, they
+// are overwritten on retry as the copy name is derived from the file ID.
+func (t *Trash) discardCopy(ctx context.Context, rec Record, err error) error {
+	if rec.CopyID == "" {
+		return err
+	}
+	if _, delErr := t.files.Delete(ctx, rec.CopyID); delErr != nil {
+		return errors.Join(err, fmt.Errorf("trash: deleting copy %s: %w", rec.CopyID, delErr))
+	}
+	return err
+}
+
+// Restore brings a trashed file back under a new ID with its metadata
+// reapplied. The record and the project storage copy of the file are
+// removed once the file is restored.
+func (t *Trash) Restore(ctx context.Context, id string) (file.Info, error) {
+	rec, err := t.store.Load(ctx, id)
+	if err != nil {
+		return file.Info{}, err
+	}
+
+	var info file.Info
+	if rec.CopyID != "" {
+		info, err = t.restoreCopy(ctx, rec)
+	} else {
+		info, err = t.restoreRemote(ctx, rec)
+	}
+	if err != nil {
+		return info, fmt.Errorf("trash: restoring file %s: %w", id, err)
+	}
+
+	if err := t.store.Delete(ctx, id); err != nil {
+		return info, fmt.Errorf("trash: removing record of file %s: %w", id, err)
+	}
+	return info, nil
+}
+
+func (t *Trash) restoreCopy(ctx context.Context, rec Record) (file.Info, error) {
+	res, err := t.files.LocalCopy(ctx, file.LocalCopyParams{
+		Source: rec.CopyID,
+		Store:  ucare.String(file.StoreTrue),
+	})
+	if err != nil {
+		return file.Info{}, err
+	}
+	info := res.Result
+
+	if _, ok := info.Metadata[TombstoneKey]; ok {
+		if err := t.metadata.Delete(ctx, info.ID, TombstoneKey); err != nil {
+			return info, err
+		}
+	}
+	if err := t.reapplyMetadata(ctx, &info, rec.Metadata); err != nil {
+		return info, err
+	}
+
+	_, err = t.files.Delete(ctx, rec.CopyID)
+	return info, err
+}
+
+func (t *Trash) restoreRemote(ctx context.Context, rec Record) (file.Info, error) {
+	if t.params.RemoteURL == nil {
+		return file.Info{}, ErrNoRemoteURL
+	}
+	srcURL, err := t.params.RemoteURL(rec.Location)
+	if err != nil {
+		return file.Info{}, err
+	}
+
+	res, err := t.upload.FromURL(ctx, upload.FromURLParams{
+		URL:      srcURL,
+		ToStore:  ucare.String(upload.ToStoreTrue),
+		Name:     ucare.String(rec.Info.OriginalFileName),
+		Metadata: rec.Metadata,
+	})
+	if err != nil {
+		return file.Info{}, err
+	}
+	uploaded, ok := res.Info()
+	if !ok {
+		select {
+		case uploaded = <-res.Done():
+		case err = <-res.Error():
+			return file.Info{}, err
+		case <-ctx.Done():
+			return file.Info{}, ctx.Err()
+		}
+	}
+	return t.files.Info(ctx, uploaded.ID, nil)
+}
+
+func (t *Trash) reapplyMetadata(
+	ctx context.Context,
+	info *file.Info,
+	meta map[string]string,
+) error {
+	for key, value := range meta {
+		if _, err := t.metadata.Set(ctx, info.ID, key, value); err != nil {
+			return err
+		}
+	}
+	info.Metadata = maps.Clone(meta)
+	return nil
+}
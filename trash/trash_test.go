@@ -0,0 +1,217 @@
+package trash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+// project emulates the project storage with file metadata
+type project struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	files    map[string]map[string]string
+	copies   int
+	requests []string
+	failMeta bool
+}
+
+func newProject(t *testing.T, ids ...string) *project {
+	p := &project{t: t, files: map[string]map[string]string{}}
+	for _, id := range ids {
+		p.files[id] = map[string]string{"owner": id + "-owner"}
+	}
+	return p
+}
+
+func (p *project) info(id string) file.Info {
+	return file.Info{
+		BasicFileInfo: file.BasicFileInfo{ID: id, OriginalFileName: id + ".jpg"},
+		Metadata:      p.files[id],
+	}
+}
+
+func (p *project) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, r.Method+" "+r.URL.Path)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/files/local_copy/":
+		var params map[string]string
+		require.NoError(p.t, json.Unmarshal(uctest.ReadBody(p.t, r), &params))
+		p.copies++
+		id := "copy" + string(rune('0'+p.copies))
+		p.files[id] = map[string]string{}
+		for k, v := range p.files[params["source"]] {
+			p.files[id][k] = v
+		}
+		uctest.RespondJSON(p.t, w, map[string]any{"result": p.info(id)})
+	case r.Method == http.MethodPost && r.URL.Path == "/files/remote_copy/":
+		var params map[string]string
+		require.NoError(p.t, json.Unmarshal(uctest.ReadBody(p.t, r), &params))
+		assert.Equal(p.t, "backup", params["target"])
+		uctest.RespondJSON(p.t, w, map[string]any{"result": "s3://bucket/" + params["source"]})
+	case r.Method == http.MethodDelete && r.URL.Path == "/files/storage/":
+		var ids []string
+		require.NoError(p.t, json.Unmarshal(uctest.ReadBody(p.t, r), &ids))
+		data := file.BatchInfo{Problems: map[string]string{}}
+		for _, id := range ids {
+			data.Results = append(data.Results, p.info(id))
+			delete(p.files, id)
+		}
+		uctest.RespondJSON(p.t, w, data)
+	case len(parts) == 4 && parts[2] == "metadata":
+		if p.failMeta {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		meta := p.files[parts[1]]
+		if r.Method == http.MethodDelete {
+			delete(meta, parts[3])
+			return
+		}
+		var value string
+		require.NoError(p.t, json.Unmarshal(uctest.ReadBody(p.t, r), &value))
+		meta[parts[3]] = value
+		uctest.RespondJSON(p.t, w, value)
+	case len(parts) == 3 && parts[2] == "storage" && r.Method == http.MethodDelete:
+		info := p.info(parts[1])
+		delete(p.files, parts[1])
+		uctest.RespondJSON(p.t, w, info)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		if _, ok := p.files[parts[1]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		uctest.RespondJSON(p.t, w, p.info(parts[1]))
+	default:
+		p.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}
+}
+
+func TestTrash_DeleteRestore(t *testing.T) {
+	t.Parallel()
+
+	p := newProject(t, "a")
+	uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+		store := DirStore(t.TempDir())
+		bin := New(uctest.NewServerClient(srv), store, Params{})
+		ctx := context.Background()
+
+		require.NoError(t, bin.Delete(ctx, "a"))
+		assert.NotContains(t, p.files, "a")
+		assert.Equal(t, map[string]string{"owner": "a-owner", TombstoneKey: "a"}, p.files["copy1"])
+
+		rec, err := store.Load(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "copy1", rec.CopyID)
+		assert.Equal(t, "a.jpg", rec.Info.OriginalFileName)
+		assert.Equal(t, map[string]string{"owner": "a-owner"}, rec.Metadata)
+
+		info, err := bin.Restore(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "copy2", info.ID)
+		assert.Equal(t, map[string]string{"owner": "a-owner"}, info.Metadata)
+		assert.Equal(t, map[string]string{"owner": "a-owner"}, p.files["copy2"])
+		assert.NotContains(t, p.files, "copy1", "trashed copy is left behind")
+
+		_, err = store.Load(ctx, "a")
+		assert.ErrorIs(t, err, ErrNotTrashed)
+		_, err = bin.Restore(ctx, "a")
+		assert.ErrorIs(t, err, ErrNotTrashed)
+	})
+}
+
+func TestTrash_BatchDelete(t *testing.T) {
+	t.Parallel()
+
+	p := newProject(t, "a", "b")
+	uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+		store := DirStore(t.TempDir())
+		bin := New(uctest.NewServerClient(srv), store, Params{Target: "backup"})
+		ctx := context.Background()
+
+		data, err := bin.BatchDelete(ctx, []string{"a", "missing", "b"})
+		require.Error(t, err)
+		assert.Len(t, data.Results, 2)
+		assert.Empty(t, p.files)
+		assert.NotContains(t, strings.Join(p.requests, "\n"), "DELETE /files/missing/")
+
+		rec, err := store.Load(ctx, "b")
+		require.NoError(t, err)
+		assert.Equal(t, "s3://bucket/b", rec.Location)
+
+		_, err = bin.Restore(ctx, "b")
+		assert.ErrorIs(t, err, ErrNoRemoteURL)
+	})
+}
+
+// failingStore fails to save records
+type failingStore struct{ Store }
+
+func (failingStore) Save(context.Context, Record) error { return errors.New("disk is full") }
+
+func TestTrash_DeleteDiscardsCopy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		store    func(t *testing.T) Store
+		failMeta bool
+		wantErr  string
+	}{
+		{
+			name:     "marking_fails",
+			store:    func(t *testing.T) Store { return DirStore(t.TempDir()) },
+			failMeta: true,
+			wantErr:  "trash: marking copy of file a",
+		},
+		{
+			name:    "recording_fails",
+			store:   func(t *testing.T) Store { return failingStore{DirStore(t.TempDir())} },
+			wantErr: "trash: recording file a: disk is full",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProject(t, "a")
+			p.failMeta = tt.failMeta
+			uctest.WithHTTPServer(t, p, func(t *testing.T, srv *httptest.Server) {
+				bin := New(uctest.NewServerClient(srv), tt.store(t), Params{})
+
+				err := bin.Delete(context.Background(), "a")
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Contains(t, p.files, "a", "file must not be deleted")
+				assert.NotContains(t, p.files, "copy1", "copy must be discarded")
+			})
+		})
+	}
+}
+
+func TestDirStore_InvalidID(t *testing.T) {
+	t.Parallel()
+
+	store := DirStore(t.TempDir())
+	for _, id := range []string{"", "..", "a/../../b"} {
+		_, err := store.Load(context.Background(), id)
+		assert.Error(t, err, id)
+		assert.NotErrorIs(t, err, ErrNotTrashed, id)
+	}
+}
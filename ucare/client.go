@@ -39,6 +39,9 @@ type client struct {
 	backends   map[config.Endpoint]Client
 	fallbackDo func(*http.Request, interface{}) error
 	cdnBase    string
+
+	conn  *http.Client
+	retry *RetryConfig
 }
 
 // NewClient initializes and configures new client for the high level API.
@@ -59,6 +62,9 @@ func NewClient(creds APICreds, conf *Config) (Client, error) {
 		},
 		fallbackDo: fallbackDoFunc(conf.HTTPClient),
 		cdnBase:    conf.CDNBase,
+
+		conn:  conf.HTTPClient,
+		retry: conf.Retry,
 	}
 
 	return &c, nil
@@ -0,0 +1,42 @@
+package ucare
+
+import "net/http"
+
+// downloader is an optional capability discovered via type assertion in
+// ClientDownload. Like cdnBaseProvider, it is not a part of the public
+// Client interface not to break external Client implementations.
+type downloader interface {
+	Download(req *http.Request) (*http.Response, error)
+}
+
+// ClientDownload makes a request outside of the Uploadcare APIs, e.g. to
+// the CDN, through the HTTP client the client is configured with, retrying
+// throttled requests according to its RetryConfig. Unlike Do, it returns
+// the response as is, the caller must close its body.
+//
+// Client implementations not exposing the capability (e.g. test doubles)
+// make the request with http.DefaultClient without retries.
+func ClientDownload(c Client, req *http.Request) (*http.Response, error) {
+	if d, ok := c.(downloader); ok {
+		return d.Download(req)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// Download implements downloader
+func (c *client) Download(req *http.Request) (*http.Response, error) {
+	for tries := 1; ; tries++ {
+		log.Debugf("making %d download request: %+v", tries, req)
+
+		resp, err := c.conn.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		_ = resp.Body.Close()
+
+		retry, err := handleThrottle(req.Context(), resp, c.retry, tries)
+		if !retry {
+			return nil, err
+		}
+	}
+}
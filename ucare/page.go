@@ -0,0 +1,25 @@
+package ucare
+
+import "errors"
+
+// ErrNoPrevPage is returned when a paginated list is navigated back from
+// its first page
+var ErrNoPrevPage = errors.New("uploadcare: no previous page to read")
+
+// ErrInvalidCursor is returned when a list is resumed from a malformed cursor
+// or from a cursor taken from a list of another resource
+var ErrInvalidCursor = errors.New("uploadcare: invalid list cursor")
+
+// PageInfo describes the page a paginated list is currently reading
+type PageInfo struct {
+	// Total is a total number of results across all pages
+	Total uint64
+	// PerPage is a number of results per single page
+	PerPage uint64
+	// Current is the URL the current page has been requested from
+	Current string
+	// Next is the URL of the next page, if any
+	Next *string
+	// Previous is the URL of the previous page, if any
+	Previous *string
+}
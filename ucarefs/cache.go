@@ -0,0 +1,66 @@
+package ucarefs
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/file"
+)
+
+// infoCache is an LRU cache of file info with entries expiring after the
+// TTL. It is not safe for concurrent use.
+type infoCache struct {
+	ttl  time.Duration
+	size int
+
+	order   *list.List // of *cachedInfo, the most recently used first
+	entries map[string]*list.Element
+}
+
+type cachedInfo struct {
+	info file.Info
+	at   time.Time
+}
+
+func newInfoCache(ttl time.Duration, size int) *infoCache {
+	return &infoCache{
+		ttl:     ttl,
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// get returns the cached info unless it is expired, expired info is evicted
+func (c *infoCache) get(id string, now time.Time) (file.Info, bool) {
+	el, ok := c.entries[id]
+	if !ok {
+		return file.Info{}, false
+	}
+	ci := el.Value.(*cachedInfo)
+	if now.Sub(ci.at) >= c.ttl {
+		c.order.Remove(el)
+		delete(c.entries, id)
+		return file.Info{}, false
+	}
+	c.order.MoveToFront(el)
+	return ci.info, true
+}
+
+// put caches the info evicting the least recently used entries over the
+// size limit
+func (c *infoCache) put(info *file.Info, now time.Time) {
+	if el, ok := c.entries[info.ID]; ok {
+		el.Value = &cachedInfo{info: *info, at: now}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[info.ID] = c.order.PushFront(&cachedInfo{info: *info, at: now})
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*cachedInfo).info.ID)
+	}
+}
+
+func (c *infoCache) len() int { return c.order.Len() }
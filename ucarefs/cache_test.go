@@ -0,0 +1,40 @@
+package ucarefs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfoCache(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	c := newInfoCache(time.Minute, 2)
+	a, b, d := testInfo("a"), testInfo("b"), testInfo("d")
+
+	c.put(&a, now)
+	c.put(&b, now)
+	_, ok := c.get("a", now)
+	assert.True(t, ok)
+
+	// "b" is the least recently used one
+	c.put(&d, now)
+	assert.Equal(t, 2, c.len())
+	_, ok = c.get("b", now)
+	assert.False(t, ok, "least recently used entry must be evicted")
+	_, ok = c.get("a", now)
+	assert.True(t, ok)
+
+	_, ok = c.get("d", now.Add(time.Minute))
+	assert.False(t, ok, "expired entry must not be returned")
+	assert.Equal(t, 1, c.len(), "expired entry must be evicted")
+
+	a.Size = 42
+	c.put(&a, now)
+	got, ok := c.get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), got.Size)
+	assert.Equal(t, 1, c.len())
+}
@@ -0,0 +1,194 @@
+package ucarefs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/file"
+)
+
+// contentFile streams the file content from the CDN. The content is
+// requested on the first Read and requested again from the new offset
+// after Seek.
+type contentFile struct {
+	fsys *FS
+	info *file.Info
+	stat fileStat
+
+	offset  int64
+	content *file.Content
+	closed  bool
+}
+
+func (f *contentFile) Stat() (fs.FileInfo, error) { return f.stat, nil }
+
+func (f *contentFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+	if f.content == nil {
+		if f.offset >= int64(f.info.Size) {
+			return 0, io.EOF
+		}
+		content, err := file.Open(f.fsys.ctx, f.fsys.client, f.info.ID, &file.OpenParams{
+			Offset: f.offset,
+		})
+		if err != nil {
+			return 0, err
+		}
+		f.content = content
+	}
+	n, err := f.content.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker, so the file can be served by http.FileServer
+func (f *contentFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(f.info.Size)
+	}
+	if offset < 0 {
+		return 0, errors.New("ucarefs: negative offset")
+	}
+	if offset != f.offset && f.content != nil {
+		_ = f.content.Close()
+		f.content = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *contentFile) Close() error {
+	if f.closed {
+		return fs.ErrClosed
+	}
+	f.closed = true
+	if f.content != nil {
+		return f.content.Close()
+	}
+	return nil
+}
+
+// dir implements the common part of the directories
+type dir struct{}
+
+func (dir) Read([]byte) (int, error) { return 0, errors.New("ucarefs: is a directory") }
+
+// rootDir lists project files
+type rootDir struct {
+	dir
+	fsys *FS
+	list *file.List
+}
+
+func (d *rootDir) Stat() (fs.FileInfo, error) { return rootStat{}, nil }
+
+func (d *rootDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.list == nil {
+		list, err := d.fsys.files.List(d.fsys.ctx, d.fsys.params.ListParams)
+		if err != nil {
+			return nil, err
+		}
+		d.list = list
+	}
+
+	entries := []fs.DirEntry{}
+	for (n <= 0 || len(entries) < n) && d.list.Next() {
+		info, err := d.list.ReadResult()
+		if err != nil {
+			return entries, err
+		}
+		d.fsys.remember(info)
+
+		var stat fs.FileInfo = d.fsys.fileStat(info)
+		if d.fsys.params.WithNames {
+			stat = dirStat{info}
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(stat))
+	}
+	if n > 0 && len(entries) == 0 {
+		return nil, io.EOF
+	}
+	return entries, nil
+}
+
+func (d *rootDir) Close() error {
+	if d.list != nil {
+		d.list.Close()
+	}
+	return nil
+}
+
+// fileDir holds a single file under its original name
+type fileDir struct {
+	dir
+	fsys *FS
+	info *file.Info
+	read bool
+}
+
+func (d *fileDir) Stat() (fs.FileInfo, error) { return dirStat{d.info}, nil }
+
+func (d *fileDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.read {
+		if n > 0 {
+			return nil, io.EOF
+		}
+		return []fs.DirEntry{}, nil
+	}
+	d.read = true
+	return []fs.DirEntry{fs.FileInfoToDirEntry(d.fsys.fileStat(d.info))}, nil
+}
+
+func (d *fileDir) Close() error { return nil }
+
+// fileStat describes a file. Sys returns its *file.Info.
+type fileStat struct {
+	name string
+	info *file.Info
+}
+
+func (s fileStat) Name() string       { return s.name }
+func (s fileStat) Size() int64        { return int64(s.info.Size) }
+func (s fileStat) Mode() fs.FileMode  { return 0o444 }
+func (s fileStat) ModTime() time.Time { return uploadedAt(s.info) }
+func (s fileStat) IsDir() bool        { return false }
+func (s fileStat) Sys() any           { return s.info }
+
+// dirStat describes a directory holding a file. Sys returns the file
+// *file.Info.
+type dirStat struct {
+	info *file.Info
+}
+
+func (s dirStat) Name() string       { return s.info.ID }
+func (s dirStat) Size() int64        { return 0 }
+func (s dirStat) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (s dirStat) ModTime() time.Time { return uploadedAt(s.info) }
+func (s dirStat) IsDir() bool        { return true }
+func (s dirStat) Sys() any           { return s.info }
+
+type rootStat struct{}
+
+func (rootStat) Name() string       { return "." }
+func (rootStat) Size() int64        { return 0 }
+func (rootStat) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (rootStat) ModTime() time.Time { return time.Time{} }
+func (rootStat) IsDir() bool        { return true }
+func (rootStat) Sys() any           { return nil }
+
+func uploadedAt(info *file.Info) time.Time {
+	if info.UploadedAt == nil {
+		return time.Time{}
+	}
+	return info.UploadedAt.Time
+}
@@ -0,0 +1,209 @@
+// Package ucarefs exposes project files as an io/fs file system.
+//
+// Files are keyed by their IDs in the root directory. With
+// Params.WithNames set, every file is put into a directory named by its ID
+// under its original file name instead, which is handy when the consumer
+// derives content types from file extensions, e.g. http.FileServer:
+//
+//	fsys := ucarefs.New(ctx, client, ucarefs.Params{WithNames: true})
+//	http.Handle("/files/", http.StripPrefix("/files/", http.FileServerFS(fsys)))
+//
+// Stat is backed by file info, which is cached for Params.CacheTTL for up to
+// Params.CacheSize most recently used files, and file
+// content is streamed from the CDN only when read. Reading the root
+// directory lists project files page by page.
+package ucarefs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/ucare"
+)
+
+// Defaults used when Params fields are not set
+const (
+	DefaultCacheTTL  = time.Minute
+	DefaultCacheSize = 10000
+)
+
+// Params holds file system params
+type Params struct {
+	// WithNames lays files out as ID/original_filename instead of ID
+	WithNames bool
+
+	// ListParams are used to list the root directory, e.g. to include
+	// stored files only. Cursor and Filter are honoured as well.
+	ListParams file.ListParams
+
+	// CacheTTL is how long file info is cached for. Negative value
+	// disables caching. Defaults to DefaultCacheTTL.
+	CacheTTL time.Duration
+
+	// CacheSize is the maximum number of files info is cached for, the
+	// least recently used ones are evicted first. Defaults to
+	// DefaultCacheSize.
+	CacheSize int
+}
+
+// FS is a read-only file system of project files. It implements fs.FS,
+// fs.StatFS and fs.ReadDirFS.
+type FS struct {
+	ctx    context.Context
+	client ucare.Client
+	files  file.Service
+	params Params
+
+	mu    sync.Mutex
+	cache *infoCache
+}
+
+var (
+	_ fs.FS        = (*FS)(nil)
+	_ fs.StatFS    = (*FS)(nil)
+	_ fs.ReadDirFS = (*FS)(nil)
+)
+
+// New returns a file system of the project files. The context is used for
+// all of the API and CDN requests made by the file system.
+func New(ctx context.Context, client ucare.Client, params Params) *FS {
+	if params.CacheTTL == 0 {
+		params.CacheTTL = DefaultCacheTTL
+	}
+	if params.CacheSize <= 0 {
+		params.CacheSize = DefaultCacheSize
+	}
+	return &FS{
+		ctx:    ctx,
+		client: client,
+		files:  file.NewService(client),
+		params: params,
+		cache:  newInfoCache(params.CacheTTL, params.CacheSize),
+	}
+}
+
+// Open implements fs.FS
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if name == "." {
+		return &rootDir{fsys: fsys}, nil
+	}
+	info, isDir, err := fsys.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if isDir {
+		return &fileDir{fsys: fsys, info: info}, nil
+	}
+	return &contentFile{fsys: fsys, info: info, stat: fsys.fileStat(info)}, nil
+}
+
+// Stat implements fs.StatFS without requesting the file content
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	if name == "." {
+		return rootStat{}, nil
+	}
+	info, isDir, err := fsys.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	if isDir {
+		return dirStat{info}, nil
+	}
+	return fsys.fileStat(info), nil
+}
+
+// ReadDir implements fs.ReadDirFS. Reading the root directory lists all of
+// the project files matching Params.ListParams.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	dir, ok := f.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	entries, err := dir.ReadDir(-1)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, err
+}
+
+// resolve looks up the file the name points to
+func (fsys *FS) resolve(op, name string) (_ *file.Info, isDir bool, _ error) {
+	if !fs.ValidPath(name) {
+		return nil, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) > 2 || (len(parts) == 2 && !fsys.params.WithNames) {
+		return nil, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	info, err := fsys.info(parts[0])
+	if err != nil {
+		return nil, false, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if len(parts) == 2 && parts[1] != fileName(info) {
+		return nil, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return info, fsys.params.WithNames && len(parts) == 1, nil
+}
+
+// info returns the file info, from the cache if it is fresh enough
+func (fsys *FS) info(id string) (*file.Info, error) {
+	fsys.mu.Lock()
+	cached, ok := fsys.cache.get(id, time.Now())
+	fsys.mu.Unlock()
+	if ok {
+		return &cached, nil
+	}
+
+	info, err := fsys.files.Info(fsys.ctx, id, nil)
+	var apiErr ucare.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+		return nil, fs.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.RemovedAt != nil {
+		return nil, fs.ErrNotExist
+	}
+	fsys.remember(&info)
+	return &info, nil
+}
+
+func (fsys *FS) remember(info *file.Info) {
+	if fsys.params.CacheTTL < 0 {
+		return
+	}
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	fsys.cache.put(info, time.Now())
+}
+
+func (fsys *FS) fileStat(info *file.Info) fileStat {
+	name := info.ID
+	if fsys.params.WithNames {
+		name = fileName(info)
+	}
+	return fileStat{name: name, info: info}
+}
+
+// fileName makes the original file name a valid path element
+func fileName(info *file.Info) string {
+	name := strings.ReplaceAll(info.OriginalFileName, "/", "_")
+	if name == "" || name == "." || name == ".." {
+		return info.ID
+	}
+	return name
+}
@@ -0,0 +1,156 @@
+package ucarefs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uploadcare/uploadcare-go/v2/file"
+	"github.com/uploadcare/uploadcare-go/v2/internal/config"
+	"github.com/uploadcare/uploadcare-go/v2/internal/uctest"
+)
+
+var testContents = map[string]string{
+	"a": "first file content",
+	"b": "second",
+}
+
+func testInfo(id string) file.Info {
+	return file.Info{
+		BasicFileInfo: file.BasicFileInfo{
+			ID:               id,
+			OriginalFileName: id + "/photo.txt",
+			Size:             uint64(len(testContents[id])),
+		},
+		UploadedAt: &config.Time{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+// project serves the API and the CDN counting file info requests
+func project(t *testing.T, infoRequests *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Path == "/files/":
+			uctest.RespondJSON(t, w, map[string]any{
+				"next":    nil,
+				"results": []file.Info{testInfo("a"), testInfo("b")},
+			})
+		case len(parts) == 2 && parts[0] == "files":
+			infoRequests.Add(1)
+			if _, ok := testContents[parts[1]]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			uctest.RespondJSON(t, w, testInfo(parts[1]))
+		case len(parts) == 1:
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(testContents[parts[0]]))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+}
+
+func newTestFS(srv *httptest.Server, params Params) *FS {
+	client := uctest.NewServerClient(srv)
+	client.CDN = srv.URL
+	return New(context.Background(), client, params)
+}
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	var infoRequests atomic.Int32
+	uctest.WithHTTPServer(t, project(t, &infoRequests), func(t *testing.T, srv *httptest.Server) {
+		require.NoError(t, fstest.TestFS(newTestFS(srv, Params{}), "a", "b"))
+		require.NoError(t, fstest.TestFS(newTestFS(srv, Params{WithNames: true}), "a/a_photo.txt", "b/b_photo.txt"))
+	})
+}
+
+func TestFS_Read(t *testing.T) {
+	t.Parallel()
+
+	var infoRequests atomic.Int32
+	uctest.WithHTTPServer(t, project(t, &infoRequests), func(t *testing.T, srv *httptest.Server) {
+		fsys := newTestFS(srv, Params{WithNames: true})
+
+		data, err := fs.ReadFile(fsys, "a/a_photo.txt")
+		require.NoError(t, err)
+		assert.Equal(t, testContents["a"], string(data))
+
+		f, err := fsys.Open("a/a_photo.txt")
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = f.(io.Seeker).Seek(6, io.SeekStart)
+		require.NoError(t, err)
+		data, err = io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "file content", string(data))
+
+		stat, err := fsys.Stat("a")
+		require.NoError(t, err)
+		assert.True(t, stat.IsDir())
+		assert.Equal(t, "a", stat.Sys().(*file.Info).ID)
+		assert.Equal(t, int32(1), infoRequests.Load(), "file info must be cached")
+
+		_, err = fsys.Stat("a/other.txt")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		_, err = fsys.Stat("missing")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		_, err = fsys.Stat("../a")
+		assert.ErrorIs(t, err, fs.ErrInvalid)
+	})
+}
+
+func TestFS_NoCache(t *testing.T) {
+	t.Parallel()
+
+	var infoRequests atomic.Int32
+	uctest.WithHTTPServer(t, project(t, &infoRequests), func(t *testing.T, srv *httptest.Server) {
+		fsys := newTestFS(srv, Params{CacheTTL: -1})
+
+		entries, err := fsys.ReadDir(".")
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "a", entries[0].Name())
+
+		for range 2 {
+			stat, err := fsys.Stat("b")
+			require.NoError(t, err)
+			assert.Equal(t, int64(6), stat.Size())
+		}
+		assert.Equal(t, int32(2), infoRequests.Load())
+	})
+}
+
+func TestFS_CacheSize(t *testing.T) {
+	t.Parallel()
+
+	var infoRequests atomic.Int32
+	uctest.WithHTTPServer(t, project(t, &infoRequests), func(t *testing.T, srv *httptest.Server) {
+		fsys := newTestFS(srv, Params{CacheSize: 1})
+
+		// listing caches both files, only the last one is kept
+		_, err := fsys.ReadDir(".")
+		require.NoError(t, err)
+		assert.Equal(t, 1, fsys.cache.len())
+
+		_, err = fsys.Stat("b")
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), infoRequests.Load())
+
+		_, err = fsys.Stat("a")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), infoRequests.Load())
+		assert.Equal(t, 1, fsys.cache.len())
+	})
+}